@@ -0,0 +1,120 @@
+package executors
+
+import (
+	"context"
+	"sync"
+)
+
+// CompletionService submit tasks to the executor, and consume the futures in completion order.
+// Take, Poll and Results consume the same queue, every completed future can only be consumed once.
+type CompletionService[T any] struct {
+	executor  ExecutorService[T]
+	locker    sync.Mutex
+	completed []Future[T]
+	signal    chan struct{}
+}
+
+func NewCompletionService[T any](executor ExecutorService[T]) *CompletionService[T] {
+	return &CompletionService[T]{
+		executor: executor,
+		signal:   make(chan struct{}),
+	}
+}
+
+// Submit submit the callable to the executor, the future will be queued after completed.
+func (s *CompletionService[T]) Submit(callable Callable[T]) (Future[T], error) {
+	f, err := s.executor.Submit(callable)
+	if err != nil {
+		return nil, err
+	}
+	whenComplete(f, func() {
+		s.push(f)
+	})
+	return f, nil
+}
+
+// SubmitFunc submit the func to the executor, the future will be queued after completed.
+func (s *CompletionService[T]) SubmitFunc(fn func(ctx context.Context) (T, error)) (Future[T], error) {
+	return s.Submit(CallableFunc[T](fn))
+}
+
+// Take wait and remove the next completed future.
+// Will return ctx.Err() if ctx done before any future completed.
+func (s *CompletionService[T]) Take(ctx context.Context) (Future[T], error) {
+	for {
+		f, ok, signal := s.poll()
+		if ok {
+			return f, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-signal:
+		}
+	}
+}
+
+// Poll remove the next completed future, will return false if no completed future.
+func (s *CompletionService[T]) Poll() (Future[T], bool) {
+	f, ok, _ := s.poll()
+	return f, ok
+}
+
+// Results return a chan to receive the completed futures until ctx done,
+// the chan will be closed after ctx done, and the future not received will be kept in the queue.
+func (s *CompletionService[T]) Results(ctx context.Context) <-chan Future[T] {
+	results := make(chan Future[T])
+	go func() {
+		defer close(results)
+		for {
+			f, err := s.Take(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case results <- f:
+			case <-ctx.Done():
+				s.unshift(f)
+				return
+			}
+		}
+	}()
+	return results
+}
+
+func (s *CompletionService[T]) push(f Future[T]) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	s.completed = append(s.completed, f)
+	s.notify()
+}
+
+// unshift put back the future taken but not consumed.
+func (s *CompletionService[T]) unshift(f Future[T]) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	s.completed = append([]Future[T]{f}, s.completed...)
+	s.notify()
+}
+
+// notify wakeup all waiting Take, should be called with lock.
+func (s *CompletionService[T]) notify() {
+	close(s.signal)
+	s.signal = make(chan struct{})
+}
+
+// poll return the signal to wait if no completed future.
+func (s *CompletionService[T]) poll() (Future[T], bool, <-chan struct{}) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if len(s.completed) == 0 {
+		return nil, false, s.signal
+	}
+	f := s.completed[0]
+	s.completed[0] = nil
+	s.completed = s.completed[1:]
+	return f, true, nil
+}
@@ -0,0 +1,129 @@
+package executors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionService(t *testing.T) {
+	service := NewCompletionService[int](NewPoolExecutorService[int](WithMaxConcurrent(10)))
+
+	submit := func(val int, delay time.Duration) {
+		_, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			time.Sleep(delay)
+			return val, nil
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("take in completion order", func(t *testing.T) {
+		_, ok := service.Poll()
+		require.False(t, ok)
+
+		submit(3, 150*time.Millisecond)
+		submit(1, 10*time.Millisecond)
+		submit(2, 80*time.Millisecond)
+
+		for want := 1; want <= 3; want++ {
+			f, err := service.Take(context.Background())
+			require.NoError(t, err)
+			got, err := f.Get(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		}
+	})
+
+	t.Run("take timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := service.Take(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("poll", func(t *testing.T) {
+		submit(1, 0)
+		require.Eventually(t, func() bool {
+			f, ok := service.Poll()
+			return ok && f.Completed()
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("results", func(t *testing.T) {
+		submit(2, 50*time.Millisecond)
+		submit(1, 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var got []int
+		for f := range service.Results(ctx) {
+			val, err := f.Get(context.Background())
+			require.NoError(t, err)
+			got = append(got, val)
+			if len(got) == 2 {
+				cancel()
+			}
+		}
+		require.Equal(t, []int{1, 2}, got)
+	})
+
+	t.Run("results not received kept", func(t *testing.T) {
+		completed := func(n int) func() bool {
+			return func() bool {
+				service.locker.Lock()
+				defer service.locker.Unlock()
+				return len(service.completed) == n
+			}
+		}
+		submit(1, 0)
+		require.Eventually(t, completed(1), time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		results := service.Results(ctx)
+		// taken and waiting for receiver
+		require.Eventually(t, completed(0), time.Second, time.Millisecond)
+		cancel()
+		require.Eventually(t, completed(1), time.Second, time.Millisecond)
+		_, ok := <-results
+		require.False(t, ok)
+
+		f, err := service.Take(context.Background())
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, got)
+	})
+
+	t.Run("results not received wakeup take", func(t *testing.T) {
+		submit(1, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		results := service.Results(ctx)
+		// taken and waiting for receiver
+		require.Eventually(t, func() bool {
+			service.locker.Lock()
+			defer service.locker.Unlock()
+			return len(service.completed) == 0
+		}, time.Second, time.Millisecond)
+
+		taken := make(chan Future[int], 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			f, _ := service.Take(ctx)
+			taken <- f
+		}()
+		// wait the Take blocked before put back
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+		_, ok := <-results
+		require.False(t, ok)
+
+		f := <-taken
+		require.NotNil(t, f)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, got)
+	})
+}
@@ -14,6 +14,8 @@ type task[T any] struct {
 	LastRunTime time.Time
 	NextRunTime time.Time
 	Location    *time.Location
+	// Runs the count of dispatched runs
+	Runs int64
 
 	nowFn func() time.Time
 }
@@ -64,10 +66,32 @@ func (t *task[T]) untilNextRun() time.Duration {
 	return t.NextRunTime.Sub(t.now())
 }
 
+// TaskInfo the snapshot of a cron task.
+type TaskInfo struct {
+	ID int32
+	// LastRunTime the time of last dispatched run, zero if never dispatched
+	LastRunTime time.Time
+	NextRunTime time.Time
+}
+
+func (t *task[T]) info() TaskInfo {
+	info := TaskInfo{
+		ID:          t.ID,
+		NextRunTime: t.NextRunTime,
+	}
+	if t.Runs > 0 {
+		info.LastRunTime = t.LastRunTime
+	}
+	return info
+}
+
 type Dispatcher[T any] interface {
 	// AddTask return func to remove task
 	AddTask(r T, expr *cronexpr.Expression, location *time.Location) func()
 
+	// Tasks return the snapshot of all tasks, ordered by ID
+	Tasks() []TaskInfo
+
 	Shutdown()
 
 	// GetReadyTask get ready task chain
@@ -1,7 +1,9 @@
 package cron
 
 import (
+	"cmp"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 func NewDispatcher[T any](logger *slog.Logger) Dispatcher[T] {
 	return &dispatcher[T]{
 		heap:      heap.New[*task[T]](taskLessThan[T]),
+		tasks:     map[int32]*task[T]{},
 		locker:    &sync.Mutex{},
 		readyChan: make(chan T, 1),
 		close:     make(chan struct{}, 1),
@@ -38,6 +41,8 @@ type dispatcher[T any] struct {
 	logger    *slog.Logger
 	sleeper   sleeper.Sleeper
 	nowFn     func() time.Time
+	// tasks the index of the tasks in heap by ID, to take snapshot without touching heap
+	tasks map[int32]*task[T]
 }
 
 func (d *dispatcher[T]) AddTask(r T, expr *cronexpr.Expression, location *time.Location) func() {
@@ -47,6 +52,7 @@ func (d *dispatcher[T]) AddTask(r T, expr *cronexpr.Expression, location *time.L
 	defer d.locker.Unlock()
 
 	d.heap.Push(t)
+	d.tasks[t.ID] = t
 
 	d.sleeper.Wakeup()
 
@@ -55,6 +61,20 @@ func (d *dispatcher[T]) AddTask(r T, expr *cronexpr.Expression, location *time.L
 	return d.getRemoveFunc(t)
 }
 
+func (d *dispatcher[T]) Tasks() []TaskInfo {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+
+	infos := make([]TaskInfo, 0, len(d.tasks))
+	for _, t := range d.tasks {
+		infos = append(infos, t.info())
+	}
+	slices.SortFunc(infos, func(a, b TaskInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return infos
+}
+
 func (d *dispatcher[T]) Shutdown() {
 	close(d.close)
 	d.sleeper.Wakeup()
@@ -68,6 +88,8 @@ func (d *dispatcher[T]) removeTask(t *task[T]) {
 	d.locker.Lock()
 	defer d.locker.Unlock()
 
+	delete(d.tasks, t.ID)
+
 	var tasks []*task[T]
 
 	for {
@@ -143,6 +165,7 @@ func (d *dispatcher[T]) takeReadyTask() (time.Duration, bool) {
 
 	_, _ = d.heap.Pop()
 	t.scheduleNextRun()
+	t.Runs++
 	d.heap.Push(t)
 
 	d.readyChan <- t.Task
@@ -188,3 +188,20 @@ func Test_dispatcher_removeTask(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, p2, peek.Task)
 }
+
+func Test_dispatcher_Tasks(t *testing.T) {
+	dispatcher := NewDispatcher[Person](slog.Default()).(*dispatcher[Person])
+
+	dispatcher.AddTask(Person{Name: "p1"}, cronexpr.MustParse("*/3 * * * * * *"), time.UTC)
+	remove := dispatcher.AddTask(Person{Name: "p2"}, cronexpr.MustParse("*/2 * * * * * *"), time.UTC)
+
+	tasks := dispatcher.Tasks()
+	require.Len(t, tasks, 2)
+	require.Less(t, tasks[0].ID, tasks[1].ID)
+	require.True(t, tasks[0].LastRunTime.IsZero())
+	require.False(t, tasks[0].NextRunTime.IsZero())
+	require.Equal(t, 2, dispatcher.heap.Size())
+
+	remove()
+	require.Len(t, dispatcher.Tasks(), 1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"time"
 )
 
@@ -18,12 +19,35 @@ type CancelFunc = func()
 
 type ErrPanic struct {
 	Cause interface{}
+
+	// Stack the stack trace captured when the panic recovered
+	Stack []byte
+
+	// Task the panicked task, nil if unknown
+	Task Runnable
+}
+
+// newErrPanic should be called in the deferred recover func to capture the stack of the panic.
+func newErrPanic(cause interface{}, task Runnable) ErrPanic {
+	return ErrPanic{
+		Cause: cause,
+		Stack: debug.Stack(),
+		Task:  task,
+	}
 }
 
 func (e ErrPanic) Error() string {
 	return fmt.Sprintf("%v", e.Cause)
 }
 
+// Unwrap return the cause if the cause is an error.
+func (e ErrPanic) Unwrap() error {
+	if err, ok := e.Cause.(error); ok {
+		return err
+	}
+	return nil
+}
+
 type Runnable interface {
 	Run(ctx context.Context)
 }
@@ -44,20 +68,53 @@ func (c CallableFunc[T]) Call(ctx context.Context) (T, error) {
 	return c(ctx)
 }
 
+// Prioritized the Runnable or Callable with priority, the higher priority will be taken first by the priority queue.
+type Prioritized interface {
+	Priority() int
+}
+
+// priorityOf return the priority of v if Prioritized, 0 otherwise.
+func priorityOf(v any) int {
+	if p, ok := v.(Prioritized); ok {
+		return p.Priority()
+	}
+	return 0
+}
+
 type Executor interface {
 	// Execute execute a task in background.
 	// Will return ErrShutdown if shutdown already.
 	// Will return ErrRejectedExecution if task out of cap.
 	Execute(Runnable) error
 
+	// ExecuteContext like Execute, the context of task will inherit the values of ctx,
+	// and the cancellation of ctx if configured.
+	ExecuteContext(ctx context.Context, r Runnable) error
+
 	// ExecuteFunc execute a func in background.
 	// Will return ErrShutdown if shutdown already.
 	// Will return ErrRejectedExecution if task out of cap.
 	ExecuteFunc(fn func(ctx context.Context)) error
 
-	// Shutdown shutdown the executor
-	// Will wait the queued task to be finish
+	// Shutdown shutdown the executor, stop accepting new tasks.
+	// Will wait the queued and running tasks to be finished,
+	// return ctx.Err() if ctx done before that.
 	Shutdown(ctx context.Context) error
+
+	// ShutdownNow shutdown the executor, stop accepting new tasks,
+	// and cancel the context of running tasks.
+	// Will return the queued tasks which never started, in submission order.
+	ShutdownNow() []Runnable
+
+	// AwaitTermination wait until all tasks finished after shutdown,
+	// return ctx.Err() if ctx done before that.
+	AwaitTermination(ctx context.Context) error
+
+	// IsShutdown return true if the executor has been shut down.
+	IsShutdown() bool
+
+	// IsTerminated return true if all tasks finished after shutdown.
+	IsTerminated() bool
 }
 
 type ExecutorService[T any] interface {
@@ -66,8 +123,22 @@ type ExecutorService[T any] interface {
 	// Submit execute a task with result async, and can get the task result via get.
 	Submit(callable Callable[T]) (Future[T], error)
 
+	// SubmitContext like Submit, the context of task will inherit the values of ctx,
+	// and the cancellation of ctx if configured.
+	SubmitContext(ctx context.Context, callable Callable[T]) (Future[T], error)
+
 	// SubmitFunc execute a func with result async, and can get the task result via get.
 	SubmitFunc(fn func(ctx context.Context) (T, error)) (Future[T], error)
+
+	// InvokeAll execute all callables, and wait until all completed.
+	// Will cancel the uncompleted tasks and return ctx.Err() if ctx done.
+	// Will cancel the submitted tasks and return the error if failed to submit any callable.
+	InvokeAll(ctx context.Context, callables []Callable[T]) ([]Future[T], error)
+
+	// InvokeAny execute all callables, and return the result of the first succeeded one.
+	// Will cancel the others after any succeeded or ctx done.
+	// Will return all errors joined if all callables failed.
+	InvokeAny(ctx context.Context, callables []Callable[T]) (T, error)
 }
 
 type CRONRule struct {
@@ -2,7 +2,9 @@ package executors
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 )
 
 type NoopErrorHandler struct {
@@ -16,6 +18,11 @@ type LogErrorHandler struct {
 }
 
 func (d LogErrorHandler) CatchError(runnable Runnable, e error) {
+	var errPanic ErrPanic
+	if errors.As(e, &errPanic) {
+		slog.Error("catch panic", slog.Any("cause", errPanic.Cause), slog.String("stack", string(errPanic.Stack)))
+		return
+	}
 	slog.Error("catch error", slog.Any("cause", e))
 }
 
@@ -25,24 +32,133 @@ type DiscardErrorHandler struct {
 func (d DiscardErrorHandler) CatchError(runnable Runnable, e error) {
 }
 
+// NoopRejectionPolicy return ErrRejectedExecution if full, ErrShutdown if shutdown.
 type NoopRejectionPolicy struct {
 }
 
-func (d NoopRejectionPolicy) RejectExecution(runnable Runnable, e Executor) error {
+func (d NoopRejectionPolicy) RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error {
+	if reason == RejectReasonShutdown {
+		return ErrShutdown
+	}
 	return ErrRejectedExecution
 }
 
+// DiscardRejectionPolicy discard the task silently if full.
 type DiscardRejectionPolicy struct {
 }
 
-func (d DiscardRejectionPolicy) RejectExecution(runnable Runnable, e Executor) error {
+func (d DiscardRejectionPolicy) RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error {
+	if reason == RejectReasonShutdown {
+		return ErrShutdown
+	}
 	return nil
 }
 
+// InlineRunner the executor can run the task in the caller goroutine with full executor semantics,
+// for the RejectionHandler to run the rejected task, like PoolExecutor.
+type InlineRunner interface {
+	// RunInline run the runnable in the caller goroutine with the same semantics as the pooled execution,
+	// ctx the context of the submitter.
+	RunInline(ctx context.Context, r Runnable) error
+}
+
+// CallerRunsRejectionPolicy run the task in the caller goroutine if full, with the context of the submitter,
+// and full executor semantics if the executor is an InlineRunner.
 type CallerRunsRejectionPolicy struct {
 }
 
-func (d CallerRunsRejectionPolicy) RejectExecution(runnable Runnable, e Executor) error {
-	runnable.Run(context.Background())
+func (d CallerRunsRejectionPolicy) RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error {
+	if reason == RejectReasonShutdown {
+		return ErrShutdown
+	}
+	if runner, ok := e.(InlineRunner); ok {
+		return runner.RunInline(ctx, runnable)
+	}
+	ctx, _ = submissionOf(ctx, runnable)
+	runnable.Run(submitterContext(ctx))
 	return nil
 }
+
+// queueAccessor the executor can evict the queued task and wait for space, like PoolExecutor.
+type queueAccessor interface {
+	// evictOldest remove the queued task submitted earliest
+	evictOldest() (Runnable, bool)
+
+	// offer execute the runnable without the RejectionHandler, with the original context and priority
+	// if ctx passed to the RejectionHandler. Will wait for space until ctx done if wait,
+	// or return ErrRejectedExecution immediately if no space.
+	offer(ctx context.Context, r Runnable, wait bool) error
+}
+
+// DiscardOldestPolicy discard the oldest queued task and retry once if full,
+// the oldest is the task submitted earliest even with WithPriorityQueue,
+// the discarded task will be canceled if it is a Future.
+// Only work for the executor with an explicit queue, like PoolExecutor with the native pool.
+type DiscardOldestPolicy struct {
+}
+
+func (d DiscardOldestPolicy) RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error {
+	if reason == RejectReasonShutdown {
+		return ErrShutdown
+	}
+	executor, ok := e.(queueAccessor)
+	if !ok {
+		return ErrRejectedExecution
+	}
+	oldest, ok := executor.evictOldest()
+	if !ok {
+		return ErrRejectedExecution
+	}
+	if f, ok := oldest.(interface{ Cancel() bool }); ok {
+		f.Cancel()
+	}
+	return executor.offer(ctx, runnable, false)
+}
+
+// BlockPolicy block the caller until space available if full,
+// will return ErrRejectedExecution if Timeout exceeded, Context done or the context of submitter done,
+// wait forever if none of them set.
+type BlockPolicy struct {
+	Timeout time.Duration
+	Context context.Context
+}
+
+func (d BlockPolicy) RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error {
+	if reason == RejectReasonShutdown {
+		return ErrShutdown
+	}
+	executor, ok := e.(queueAccessor)
+	if !ok {
+		return ErrRejectedExecution
+	}
+	// stop waiting if any of the context of submitter, Context and Timeout done
+	ctx, cancelCause := context.WithCancelCause(ctx)
+	defer cancelCause(nil)
+	if d.Context != nil {
+		stop := context.AfterFunc(d.Context, func() {
+			cancelCause(context.Cause(d.Context))
+		})
+		defer stop()
+	}
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+	return executor.offer(ctx, runnable, true)
+}
+
+// FallbackExecutorPolicy execute the task by the fallback Executor if full.
+type FallbackExecutorPolicy struct {
+	Executor Executor
+}
+
+func (d FallbackExecutorPolicy) RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error {
+	if reason == RejectReasonShutdown {
+		return ErrShutdown
+	}
+	if ctx, _ = submissionOf(ctx, runnable); ctx != nil {
+		return d.Executor.ExecuteContext(ctx, runnable)
+	}
+	return d.Executor.Execute(runnable)
+}
@@ -0,0 +1,290 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFullExecutor return an executor with one blocked running task and one queued task.
+func newFullExecutor(t *testing.T, handler RejectionHandler, opts ..._PoolExecutorOption) (ExecutorService[int], Future[int], chan struct{}) {
+	opts = append(opts, WithMaxConcurrent(1), WithMaxBlockingTasks(1), WithRejectionHandler(handler))
+	executor := NewPoolExecutorService[int](opts...)
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	_, err := executor.SubmitFunc(func(ctx context.Context) (int, error) {
+		close(started)
+		<-block
+		return 0, nil
+	})
+	require.NoError(t, err)
+	<-started
+
+	queued, err := executor.SubmitFunc(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	return executor, queued, block
+}
+
+func TestRejectionPolicy_Reason(t *testing.T) {
+	var reasons []RejectReason
+	executor, _, block := newFullExecutor(t, RejectionHandlerFunc(func(ctx context.Context, r Runnable, e Executor, reason RejectReason) error {
+		reasons = append(reasons, reason)
+		return NoopRejectionPolicy{}.RejectExecution(ctx, r, e, reason)
+	}))
+
+	require.ErrorIs(t, executor.ExecuteFunc(func(ctx context.Context) {}), ErrRejectedExecution)
+	close(block)
+	require.NoError(t, executor.Shutdown(context.Background()))
+	require.ErrorIs(t, executor.ExecuteFunc(func(ctx context.Context) {}), ErrShutdown)
+	require.Equal(t, []RejectReason{RejectReasonFull, RejectReasonShutdown}, reasons)
+}
+
+func TestDiscardOldestPolicy(t *testing.T) {
+	executor, oldest, block := newFullExecutor(t, DiscardOldestPolicy{})
+	defer executor.Shutdown(context.Background())
+
+	f, err := executor.SubmitFunc(func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	require.NoError(t, err)
+	require.True(t, oldest.Canceled())
+
+	close(block)
+	got, err := f.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, got)
+}
+
+func TestBlockPolicy(t *testing.T) {
+	t.Run("space available", func(t *testing.T) {
+		executor, _, block := newFullExecutor(t, BlockPolicy{Timeout: time.Second})
+		defer executor.Shutdown(context.Background())
+
+		time.AfterFunc(20*time.Millisecond, func() {
+			close(block)
+		})
+		f, err := executor.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 2, nil
+		})
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, got)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		executor, _, block := newFullExecutor(t, BlockPolicy{Timeout: 20 * time.Millisecond})
+		defer executor.Shutdown(context.Background())
+		defer close(block)
+
+		err := executor.ExecuteFunc(func(ctx context.Context) {})
+		require.ErrorIs(t, err, ErrRejectedExecution)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		executor, _, block := newFullExecutor(t, BlockPolicy{Context: ctx})
+		defer executor.Shutdown(context.Background())
+		defer close(block)
+
+		time.AfterFunc(20*time.Millisecond, cancel)
+		err := executor.ExecuteFunc(func(ctx context.Context) {})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestDiscardOldestPolicy_PriorityQueue(t *testing.T) {
+	executor, oldest, block := newFullExecutor(t, DiscardOldestPolicy{}, WithPriorityQueue())
+	pool := executor.(*PoolExecutor[int])
+	defer executor.Shutdown(context.Background())
+	defer close(block)
+
+	// queue a high priority task after the oldest one
+	require.NoError(t, pool.SetMaxBlockingTasks(2))
+	high, err := pool.SubmitWithPriority(CallableFunc[int](func(ctx context.Context) (int, error) {
+		return 2, nil
+	}), 5)
+	require.NoError(t, err)
+
+	_, err = executor.SubmitFunc(func(ctx context.Context) (int, error) {
+		return 3, nil
+	})
+	require.NoError(t, err)
+	require.True(t, oldest.Canceled())
+	require.False(t, high.Canceled())
+}
+
+func TestBlockPolicy_Submission(t *testing.T) {
+	t.Run("context", func(t *testing.T) {
+		executor, _, block := newFullExecutor(t, BlockPolicy{Timeout: time.Second})
+		defer executor.Shutdown(context.Background())
+
+		time.AfterFunc(20*time.Millisecond, func() {
+			close(block)
+		})
+		ctx := context.WithValue(context.Background(), traceKey{}, "submitter")
+		f, err := executor.SubmitContext(ctx, CallableFunc[int](func(ctx context.Context) (int, error) {
+			return len(ctx.Value(traceKey{}).(string)), nil
+		}))
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, len("submitter"), got)
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		executor := internalNewPoolExecutorService[int](WithPriorityQueue(), WithMaxConcurrent(1), WithMaxBlockingTasks(2),
+			WithRejectionHandler(BlockPolicy{Timeout: time.Second}))
+		defer executor.Shutdown(context.Background())
+
+		var (
+			locker sync.Mutex
+			order  []string
+		)
+		record := func(name string) func(ctx context.Context) {
+			return func(ctx context.Context) {
+				locker.Lock()
+				defer locker.Unlock()
+				order = append(order, name)
+			}
+		}
+		block1 := make(chan struct{})
+		block2 := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, executor.ExecuteFunc(func(ctx context.Context) {
+			close(started)
+			<-block1
+		}))
+		<-started
+		require.NoError(t, executor.ExecuteFunc(func(ctx context.Context) {
+			<-block2
+			record("low1")(ctx)
+		}))
+		require.NoError(t, executor.ExecuteFunc(record("low2")))
+
+		blocked := make(chan error)
+		go func() {
+			blocked <- executor.ExecuteWithPriority(RunnableFunc(record("high")), 5)
+		}()
+		require.Eventually(t, func() bool {
+			return executor.Stats().Rejected == 1
+		}, time.Second, time.Millisecond)
+
+		// low1 started, high queued after low2 with the original priority
+		close(block1)
+		require.NoError(t, <-blocked)
+		close(block2)
+		require.NoError(t, executor.Shutdown(context.Background()))
+		require.Equal(t, []string{"low1", "high", "low2"}, order)
+	})
+}
+
+type countingFallback struct {
+	Executor
+	executed atomic.Int32
+}
+
+func (e *countingFallback) Execute(r Runnable) error {
+	e.executed.Add(1)
+	r.Run(context.Background())
+	return nil
+}
+
+func TestFallbackExecutorPolicy(t *testing.T) {
+	fallback := &countingFallback{}
+	executor, _, block := newFullExecutor(t, FallbackExecutorPolicy{Executor: fallback})
+
+	var ran atomic.Bool
+	require.NoError(t, executor.ExecuteFunc(func(ctx context.Context) {
+		ran.Store(true)
+	}))
+	require.True(t, ran.Load())
+
+	close(block)
+	require.NoError(t, executor.Shutdown(context.Background()))
+	require.ErrorIs(t, executor.ExecuteFunc(func(ctx context.Context) {}), ErrShutdown)
+	require.Equal(t, int32(1), fallback.executed.Load())
+}
+
+func TestCallerRunsRejectionPolicy(t *testing.T) {
+	var caught atomic.Value
+	// the blocked and queued tasks will send to errs after block closed
+	interceptor := &recordInterceptor{name: "caller", errs: make(chan error, 4)}
+	executor, _, block := newFullExecutor(t, CallerRunsRejectionPolicy{},
+		WithExecuteTimeout(time.Second),
+		WithInterceptors(interceptor),
+		WithErrorHandler(ErrorHandlerFunc(func(r Runnable, e error) {
+			caught.Store(e)
+		})))
+	defer executor.Shutdown(context.Background())
+	defer close(block)
+
+	t.Run("executor semantics", func(t *testing.T) {
+		f, err := executor.SubmitFunc(func(ctx context.Context) (int, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				return 0, errors.New("no deadline")
+			}
+			return len(ctx.Value(traceKey{}).(string)), nil
+		})
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, len("caller"), got)
+		require.NoError(t, <-interceptor.errs)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		require.NotPanics(t, func() {
+			require.NoError(t, executor.ExecuteFunc(panicTask))
+		})
+		var errPanic ErrPanic
+		require.ErrorAs(t, caught.Load().(error), &errPanic)
+		require.ErrorAs(t, <-interceptor.errs, &errPanic)
+		require.Equal(t, int64(1), executor.(*PoolExecutor[int]).Stats().Panicked)
+	})
+}
+
+func TestCallerRunsRejectionPolicy_Submission(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	ctx = context.WithValue(ctx, traceKey{}, "submitter")
+
+	check := func(ctx context.Context) (int, error) {
+		if got, ok := ctx.Deadline(); !ok || !got.Equal(deadline) {
+			return 0, errors.New("no submitter deadline")
+		}
+		return len(ctx.Value(traceKey{}).(string)), nil
+	}
+
+	t.Run("inline", func(t *testing.T) {
+		executor, _, block := newFullExecutor(t, CallerRunsRejectionPolicy{}, WithInheritCancel(true))
+		defer executor.Shutdown(context.Background())
+		defer close(block)
+
+		f, err := executor.SubmitContext(ctx, CallableFunc[int](check))
+		require.NoError(t, err)
+		require.True(t, f.Completed())
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, len("submitter"), got)
+	})
+
+	t.Run("not inline runner", func(t *testing.T) {
+		f := NewFutureTask[int](CallableFunc[int](check))
+		err := CallerRunsRejectionPolicy{}.RejectExecution(rejectionContext(ctx, 0), f, nil, RejectReasonFull)
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, len("submitter"), got)
+	})
+}
@@ -0,0 +1,406 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+type _FairExecutorOption func(opts *fairExecutorOptions)
+
+type fairExecutorOptions struct {
+	// Concurrency the max count of tasks dispatched to the underlying executor at the same time
+	Concurrency int
+	// MaxInFlight the max count of running tasks per tenant, unlimited if 0
+	MaxInFlight int
+	// MaxQueued the max count of waiting tasks per tenant, unlimited if 0
+	MaxQueued int
+	// Weight return the weight of tenant, the tasks can be dispatched in a round
+	Weight func(tenant string) int
+
+	RejectionHandler RejectionHandler
+}
+
+var _DefaultFairExecutorOptions = fairExecutorOptions{
+	Concurrency:      _DefaultPoolExecutorOptions.MaxConcurrent,
+	Weight:           func(tenant string) int { return 1 },
+	RejectionHandler: NoopRejectionPolicy{},
+}
+
+// WithFairConcurrency set the max count of tasks dispatched to the underlying executor at the same time,
+// should not be greater than the concurrency of the underlying executor,
+// default is MaxConcurrent of the PoolExecutor.
+func WithFairConcurrency(concurrency int) _FairExecutorOption {
+	return func(opts *fairExecutorOptions) {
+		opts.Concurrency = concurrency
+	}
+}
+
+// WithTenantMaxInFlight set the max count of running tasks per tenant, unlimited if 0.
+func WithTenantMaxInFlight(max int) _FairExecutorOption {
+	return func(opts *fairExecutorOptions) {
+		opts.MaxInFlight = max
+	}
+}
+
+// WithTenantMaxQueued set the max count of waiting tasks per tenant, unlimited if 0.
+// The task will be rejected by the RejectionHandler if the tenant queue is full.
+func WithTenantMaxQueued(max int) _FairExecutorOption {
+	return func(opts *fairExecutorOptions) {
+		opts.MaxQueued = max
+	}
+}
+
+// WithTenantWeight set the weight of tenants, the count of tasks can be dispatched in a round,
+// default 1 for every tenant.
+func WithTenantWeight(weight func(tenant string) int) _FairExecutorOption {
+	return func(opts *fairExecutorOptions) {
+		opts.Weight = weight
+	}
+}
+
+// WithTenantRejectionHandler set the handler of the tasks rejected because the tenant queue is full or shutdown,
+// or rejected by the underlying executor when dispatched, the Future will be completed with the error of handler then.
+// The Executor passed to the handler is the *TenantExecutor of the tenant.
+func WithTenantRejectionHandler(handler RejectionHandler) _FairExecutorOption {
+	return func(opts *fairExecutorOptions) {
+		opts.RejectionHandler = handler
+	}
+}
+
+// FairExecutor share the workers of the underlying executor across tenants fairly.
+// The tasks are queued per tenant, and dispatched to the underlying executor by weighted round-robin,
+// so a noisy tenant can not starve the others.
+type FairExecutor struct {
+	executor Executor
+	opts     fairExecutorOptions
+
+	locker  sync.Mutex
+	tenants map[string]*tenantQueue
+	// ring the tenants with waiting tasks, in round-robin order
+	ring   []*tenantQueue
+	cursor int
+	// inFlight the count of dispatched tasks
+	inFlight int
+	// queued the count of waiting tasks of all tenants
+	queued   int
+	shutdown atomic.Bool
+	// drained closed after shutdown and all waiting tasks dispatched
+	drained chan struct{}
+}
+
+type tenantQueue struct {
+	tenant   string
+	tasks    []*fairTask
+	inFlight int
+	inRing   bool
+	// credits the count of tasks can be dispatched in current round
+	credits int
+}
+
+// fairTask the task dispatched to the underlying executor.
+type fairTask struct {
+	executor *FairExecutor
+	tenant   *tenantQueue
+	runnable Runnable
+	ctx      context.Context
+	// finished set once run or canceled, to release the slot exactly once
+	finished atomic.Bool
+}
+
+func (t *fairTask) Run(ctx context.Context) {
+	if t.finished.Swap(true) {
+		return
+	}
+	defer t.executor.done(t.tenant)
+	t.runnable.Run(ctx)
+}
+
+// Cancel will be called if evicted from the queue of the underlying executor, like DiscardOldestPolicy,
+// release the slot and complete the task with ErrRejectedExecution.
+func (t *fairTask) Cancel() bool {
+	if t.finished.Swap(true) {
+		return false
+	}
+	if f, ok := t.runnable.(completable); ok {
+		f.completeError(ErrRejectedExecution)
+	}
+	t.executor.done(t.tenant)
+	return true
+}
+
+func (t *fairTask) failure() error {
+	return failureOf(t.runnable)
+}
+
+// NewFairExecutor create a FairExecutor run the tasks by executor.
+func NewFairExecutor(executor Executor, opts ..._FairExecutorOption) *FairExecutor {
+	var opt = _DefaultFairExecutorOptions
+	if p, ok := executor.(interface{ MaxConcurrent() int }); ok {
+		opt.Concurrency = p.MaxConcurrent()
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	return &FairExecutor{
+		executor: executor,
+		opts:     opt,
+		tenants:  map[string]*tenantQueue{},
+		drained:  make(chan struct{}),
+	}
+}
+
+// Tenant return the Executor to execute the tasks of tenant.
+func (e *FairExecutor) Tenant(tenant string) *TenantExecutor {
+	return &TenantExecutor{FairExecutor: e, tenant: tenant}
+}
+
+// ExecuteTenant execute the runnable as the task of tenant.
+func (e *FairExecutor) ExecuteTenant(tenant string, r Runnable) error {
+	return e.execute(nil, tenant, r)
+}
+
+// SubmitTenant submit the callable as the task of tenant.
+func SubmitTenant[T any](executor *FairExecutor, tenant string, callable Callable[T]) (Future[T], error) {
+	f := NewFutureTask[T](callable)
+	err := executor.ExecuteTenant(tenant, f)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (e *FairExecutor) execute(ctx context.Context, tenant string, r Runnable) error {
+	if reason, ok := e.enqueue(ctx, tenant, r); !ok {
+		return e.opts.RejectionHandler.RejectExecution(submitterContext(ctx), r, e.Tenant(tenant), reason)
+	}
+	e.dispatch()
+	return nil
+}
+
+// enqueue add the task to the tenant queue, will return the reason and false if shutdown or the tenant queue is full.
+func (e *FairExecutor) enqueue(ctx context.Context, tenant string, r Runnable) (RejectReason, bool) {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+
+	if e.shutdown.Load() {
+		return RejectReasonShutdown, false
+	}
+	q, ok := e.tenants[tenant]
+	if !ok {
+		q = &tenantQueue{tenant: tenant}
+		e.tenants[tenant] = q
+	}
+	if e.opts.MaxQueued > 0 && len(q.tasks) >= e.opts.MaxQueued {
+		e.tryRemoveTenant(q)
+		return RejectReasonFull, false
+	}
+	q.tasks = append(q.tasks, &fairTask{executor: e, tenant: q, runnable: r, ctx: ctx})
+	e.queued++
+	if !q.inRing {
+		q.inRing = true
+		q.credits = e.weight(q)
+		e.ring = append(e.ring, q)
+	}
+	return 0, true
+}
+
+// dispatch dispatch the waiting tasks to the underlying executor until no free slot.
+func (e *FairExecutor) dispatch() {
+	for {
+		e.locker.Lock()
+		task, ok := e.pick()
+		e.locker.Unlock()
+		if !ok {
+			return
+		}
+
+		if err := e.submit(task); err != nil {
+			e.release(task.tenant)
+			reason := RejectReasonFull
+			if errors.Is(err, ErrShutdown) {
+				reason = RejectReasonShutdown
+			}
+			// the task accepted already, complete the future with the error if the handler can not handle it
+			err = e.opts.RejectionHandler.RejectExecution(submitterContext(task.ctx), task.runnable, e.Tenant(task.tenant.tenant), reason)
+			if f, ok := task.runnable.(completable); ok && err != nil {
+				f.completeError(err)
+			}
+		}
+	}
+}
+
+// submit submit the task to the underlying executor,
+// bypass the RejectionHandler of PoolExecutor, so the task will never be discarded silently.
+func (e *FairExecutor) submit(task *fairTask) error {
+	if executor, ok := e.executor.(queueAccessor); ok {
+		return executor.offer(task.ctx, task, false)
+	}
+	if task.ctx != nil {
+		return e.executor.ExecuteContext(task.ctx, task)
+	}
+	return e.executor.Execute(task)
+}
+
+// pick pick the next task to dispatch by weighted round-robin, should be called with lock.
+func (e *FairExecutor) pick() (*fairTask, bool) {
+	if e.inFlight >= e.opts.Concurrency {
+		return nil, false
+	}
+	// visit every tenant once, and the first one again after its credits refilled
+	for visited := 0; len(e.ring) > 0 && visited <= len(e.ring); visited++ {
+		q := e.ring[e.cursor]
+		if q.credits > 0 && (e.opts.MaxInFlight == 0 || q.inFlight < e.opts.MaxInFlight) {
+			task := q.tasks[0]
+			q.tasks[0] = nil
+			q.tasks = q.tasks[1:]
+			q.credits--
+			q.inFlight++
+			e.inFlight++
+			e.queued--
+			if len(q.tasks) == 0 {
+				e.removeFromRing()
+			}
+			e.tryDrained()
+			return task, true
+		}
+		e.cursor = (e.cursor + 1) % len(e.ring)
+		e.ring[e.cursor].credits = e.weight(e.ring[e.cursor])
+	}
+	return nil, false
+}
+
+// removeFromRing remove the tenant at cursor from ring, should be called with lock.
+func (e *FairExecutor) removeFromRing() {
+	q := e.ring[e.cursor]
+	q.inRing = false
+	e.ring = append(e.ring[:e.cursor], e.ring[e.cursor+1:]...)
+	if len(e.ring) == 0 {
+		e.cursor = 0
+		return
+	}
+	e.cursor %= len(e.ring)
+	e.ring[e.cursor].credits = e.weight(e.ring[e.cursor])
+}
+
+func (e *FairExecutor) weight(q *tenantQueue) int {
+	return max(1, e.opts.Weight(q.tenant))
+}
+
+// done release the slot of the finished task, and dispatch the next.
+func (e *FairExecutor) done(q *tenantQueue) {
+	e.release(q)
+	e.dispatch()
+}
+
+// release release the slot of the dispatched task.
+func (e *FairExecutor) release(q *tenantQueue) {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+
+	q.inFlight--
+	e.inFlight--
+	e.tryRemoveTenant(q)
+}
+
+// tryRemoveTenant remove the idle tenant, should be called with lock.
+func (e *FairExecutor) tryRemoveTenant(q *tenantQueue) {
+	if q.inRing || q.inFlight > 0 {
+		return
+	}
+	delete(e.tenants, q.tenant)
+}
+
+// tryDrained close drained if shutdown and all waiting tasks dispatched, should be called with lock.
+func (e *FairExecutor) tryDrained() {
+	if !e.shutdown.Load() || e.queued > 0 {
+		return
+	}
+	select {
+	case <-e.drained:
+	default:
+		close(e.drained)
+	}
+}
+
+// Shutdown reject new tasks, wait all the waiting tasks dispatched,
+// and then shutdown the underlying executor.
+func (e *FairExecutor) Shutdown(ctx context.Context) error {
+	e.locker.Lock()
+	e.shutdown.Store(true)
+	e.tryDrained()
+	e.locker.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-e.drained:
+	}
+	return e.executor.Shutdown(ctx)
+}
+
+// ShutdownNow reject new tasks, shutdown the underlying executor immediately,
+// and return the waiting tasks of all tenants and the underlying executor.
+func (e *FairExecutor) ShutdownNow() []Runnable {
+	e.locker.Lock()
+	e.shutdown.Store(true)
+	var runnables []Runnable
+	for _, q := range e.ring {
+		for _, task := range q.tasks {
+			runnables = append(runnables, task.runnable)
+		}
+		q.tasks = nil
+		q.inRing = false
+		e.tryRemoveTenant(q)
+	}
+	e.ring = nil
+	e.cursor = 0
+	e.queued = 0
+	e.tryDrained()
+	e.locker.Unlock()
+
+	for _, r := range e.executor.ShutdownNow() {
+		if task, ok := r.(*fairTask); ok {
+			r = task.runnable
+		}
+		runnables = append(runnables, r)
+	}
+	return runnables
+}
+
+func (e *FairExecutor) AwaitTermination(ctx context.Context) error {
+	return e.executor.AwaitTermination(ctx)
+}
+
+func (e *FairExecutor) IsShutdown() bool {
+	return e.shutdown.Load()
+}
+
+func (e *FairExecutor) IsTerminated() bool {
+	return e.executor.IsTerminated()
+}
+
+// TenantExecutor the Executor of a tenant in FairExecutor,
+// the lifecycle methods will apply to the whole FairExecutor.
+type TenantExecutor struct {
+	*FairExecutor
+	tenant string
+}
+
+// Tenant return the tenant of the executor.
+func (e *TenantExecutor) Tenant() string {
+	return e.tenant
+}
+
+func (e *TenantExecutor) Execute(r Runnable) error {
+	return e.execute(nil, e.tenant, r)
+}
+
+func (e *TenantExecutor) ExecuteContext(ctx context.Context, r Runnable) error {
+	return e.execute(ctx, e.tenant, r)
+}
+
+func (e *TenantExecutor) ExecuteFunc(fn func(ctx context.Context)) error {
+	return e.Execute(RunnableFunc(fn))
+}
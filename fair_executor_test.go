@@ -0,0 +1,212 @@
+package executors
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runFairOrder block the only slot, submit the tasks of tenants, and return the order of tenants run.
+func runFairOrder(t *testing.T, executor *FairExecutor, tenants ...string) []string {
+	block := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, executor.ExecuteTenant("blocker", RunnableFunc(func(ctx context.Context) {
+		close(started)
+		<-block
+	})))
+	<-started
+
+	var (
+		locker sync.Mutex
+		order  []string
+		wg     sync.WaitGroup
+	)
+	for _, tenant := range tenants {
+		wg.Add(1)
+		require.NoError(t, executor.ExecuteTenant(tenant, RunnableFunc(func(ctx context.Context) {
+			defer wg.Done()
+			locker.Lock()
+			defer locker.Unlock()
+			order = append(order, tenant)
+		})))
+	}
+	close(block)
+	wg.Wait()
+	return order
+}
+
+func TestFairExecutor(t *testing.T) {
+	t.Run("round-robin", func(t *testing.T) {
+		executor := NewFairExecutor(NewPoolExecutor(), WithFairConcurrency(1))
+		defer executor.Shutdown(context.Background())
+
+		got := runFairOrder(t, executor, "a", "a", "a", "a", "b", "b")
+		require.Equal(t, []string{"a", "b", "a", "b", "a", "a"}, got)
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		executor := NewFairExecutor(NewPoolExecutor(), WithFairConcurrency(1), WithTenantWeight(func(tenant string) int {
+			if tenant == "a" {
+				return 2
+			}
+			return 1
+		}))
+		defer executor.Shutdown(context.Background())
+
+		got := runFairOrder(t, executor, "a", "a", "a", "a", "b", "b")
+		require.Equal(t, []string{"a", "a", "b", "a", "a", "b"}, got)
+	})
+
+	t.Run("max in flight", func(t *testing.T) {
+		executor := NewFairExecutor(NewPoolExecutor(WithMaxConcurrent(4)), WithTenantMaxInFlight(1))
+
+		var (
+			running atomic.Int32
+			overlap atomic.Bool
+		)
+		for range 20 {
+			require.NoError(t, executor.Tenant("a").ExecuteFunc(func(ctx context.Context) {
+				if running.Add(1) > 1 {
+					overlap.Store(true)
+				}
+				defer running.Add(-1)
+			}))
+		}
+		require.NoError(t, executor.Shutdown(context.Background()))
+		require.False(t, overlap.Load())
+		require.True(t, executor.IsTerminated())
+	})
+
+	t.Run("max queued", func(t *testing.T) {
+		var rejected []string
+		executor := NewFairExecutor(NewPoolExecutor(), WithFairConcurrency(1), WithTenantMaxQueued(1),
+			WithTenantRejectionHandler(RejectionHandlerFunc(func(ctx context.Context, r Runnable, e Executor, reason RejectReason) error {
+				rejected = append(rejected, e.(*TenantExecutor).Tenant()+":"+reason.String())
+				return NoopRejectionPolicy{}.RejectExecution(ctx, r, e, reason)
+			})))
+
+		block := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, executor.ExecuteTenant("a", RunnableFunc(func(ctx context.Context) {
+			close(started)
+			<-block
+		})))
+		<-started
+		require.NoError(t, executor.ExecuteTenant("a", RunnableFunc(func(ctx context.Context) {})))
+		require.ErrorIs(t, executor.ExecuteTenant("a", RunnableFunc(func(ctx context.Context) {})), ErrRejectedExecution)
+		// the other tenant not affected
+		require.NoError(t, executor.ExecuteTenant("b", RunnableFunc(func(ctx context.Context) {})))
+		require.Equal(t, []string{"a:full"}, rejected)
+
+		require.Len(t, executor.ShutdownNow(), 2)
+		close(block)
+		require.ErrorIs(t, executor.ExecuteTenant("a", RunnableFunc(func(ctx context.Context) {})), ErrShutdown)
+		require.Equal(t, []string{"a:full", "a:shutdown"}, rejected)
+	})
+
+	t.Run("submit", func(t *testing.T) {
+		executor := NewFairExecutor(NewPoolExecutor())
+		defer executor.Shutdown(context.Background())
+
+		f, err := SubmitTenant[int](executor, "a", CallableFunc[int](func(ctx context.Context) (int, error) {
+			return 1, nil
+		}))
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, got)
+	})
+	t.Run("rejected by the underlying executor", func(t *testing.T) {
+		pool := NewPoolExecutor(WithMaxConcurrent(1), WithMaxBlockingTasks(1))
+		executor := NewFairExecutor(pool)
+
+		// saturate the shared pool
+		block := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {
+			close(started)
+			<-block
+		}))
+		<-started
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {}))
+
+		f, err := SubmitTenant[int](executor, "a", CallableFunc[int](func(ctx context.Context) (int, error) {
+			return 1, nil
+		}))
+		require.NoError(t, err)
+		_, err = f.Get(context.Background())
+		require.ErrorIs(t, err, ErrRejectedExecution)
+
+		close(block)
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+
+	inFlight := func(executor *FairExecutor) int {
+		executor.locker.Lock()
+		defer executor.locker.Unlock()
+		return executor.inFlight
+	}
+
+	t.Run("discarded by the underlying executor", func(t *testing.T) {
+		pool := NewPoolExecutor(WithMaxConcurrent(1), WithMaxBlockingTasks(1),
+			WithRejectionHandler(DiscardRejectionPolicy{}))
+		executor := NewFairExecutor(pool, WithFairConcurrency(2))
+
+		// saturate the shared pool
+		block := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {
+			close(started)
+			<-block
+		}))
+		<-started
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {}))
+
+		f, err := SubmitTenant[int](executor, "a", CallableFunc[int](func(ctx context.Context) (int, error) {
+			return 1, nil
+		}))
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = f.Get(ctx)
+		require.ErrorIs(t, err, ErrRejectedExecution)
+		require.Equal(t, 0, inFlight(executor))
+
+		close(block)
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+
+	t.Run("evicted by the underlying executor", func(t *testing.T) {
+		pool := NewPoolExecutor(WithMaxConcurrent(1), WithMaxBlockingTasks(1),
+			WithRejectionHandler(DiscardOldestPolicy{}))
+		executor := NewFairExecutor(pool, WithFairConcurrency(2))
+
+		block := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {
+			close(started)
+			<-block
+		}))
+		<-started
+
+		// queued in the shared pool, and then evicted by the next task
+		f, err := SubmitTenant[int](executor, "a", CallableFunc[int](func(ctx context.Context) (int, error) {
+			return 1, nil
+		}))
+		require.NoError(t, err)
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = f.Get(ctx)
+		require.ErrorIs(t, err, ErrRejectedExecution)
+		require.Equal(t, 0, inFlight(executor))
+
+		close(block)
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+}
@@ -3,25 +3,74 @@ package executors
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
 	ErrFutureCanceled = errors.New("future canceled")
+	ErrEmptyFutures   = errors.New("empty futures")
+	ErrFutureTimeout  = errors.New("future timeout")
 )
 
 type ThenFunction[T any] func(val T) error
 type CatchFunction func(err error)
+type FinallyFunction func()
+type CompleteFunction[T any] func(val T, err error)
 
+// Future the result of an async task.
+// Then, Catch, Finally and OnComplete can be called multiple times,
+// every callback will be called exactly once after the future completed,
+// and return a new future which will be completed after the callback called.
+// The callbacks run in the goroutine which completed the future,
+// or the goroutine registering the callback if the future completed already,
+// use ThenAsync and CatchAsync to run the callbacks in an Executor.
 type Future[T any] interface {
 	Get(ctx context.Context) (T, error)
-	Then(thenFunc ThenFunction[T]) NotThenableFuture[T]
-	Catch(catchFunc CatchFunction) NotChainableFuture[T]
+
+	// Then call thenFunc after the future succeeded.
+	// The new future will be completed with the error returned by thenFunc if not nil,
+	// otherwise with the result of this future.
+	Then(thenFunc ThenFunction[T]) Future[T]
+
+	// ThenAsync like Then, but thenFunc will be executed by executor.
+	// Will use the callback executor of the future if executor is nil,
+	// and run in a new goroutine if no callback executor either.
+	ThenAsync(thenFunc ThenFunction[T], executor Executor) Future[T]
+
+	// Catch call catchFunc after the future failed or canceled.
+	// The new future will be completed with the result of this future.
+	Catch(catchFunc CatchFunction) Future[T]
+
+	// CatchAsync like Catch, but catchFunc will be executed by executor.
+	// Will use the callback executor of the future if executor is nil,
+	// and run in a new goroutine if no callback executor either.
+	CatchAsync(catchFunc CatchFunction, executor Executor) Future[T]
+
+	// Finally call finallyFunc after the future completed.
+	// The new future will be completed with the result of this future.
+	Finally(finallyFunc FinallyFunction) Future[T]
+
+	// OnComplete call completeFunc with the result after the future completed.
+	// The new future will be completed with the result of this future.
+	OnComplete(completeFunc CompleteFunction[T]) Future[T]
+
+	// OrTimeout complete the future with ErrFutureTimeout if not completed in timeout,
+	// and cancel the context of the callable.
+	OrTimeout(timeout time.Duration) Future[T]
+
+	// CompleteOnTimeout complete the future with val if not completed in timeout,
+	// and cancel the context of the callable.
+	CompleteOnTimeout(val T, timeout time.Duration) Future[T]
+
 	Cancel() bool
 	Canceled() bool
 	Completed() bool
 	CompletedError() bool
 }
 
+// NotThenableFuture
+//
+// Deprecated: Then and Catch return Future now, use Future instead.
 type NotThenableFuture[T any] interface {
 	Get(ctx context.Context) (T, error)
 	Catch(catchFunc CatchFunction) NotChainableFuture[T]
@@ -31,6 +80,9 @@ type NotThenableFuture[T any] interface {
 	CompletedError() bool
 }
 
+// NotChainableFuture
+//
+// Deprecated: Then and Catch return Future now, use Future instead.
 type NotChainableFuture[T any] interface {
 	Get(ctx context.Context) (T, error)
 	Cancel() bool
@@ -0,0 +1,142 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Result the value and error of a completed future.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// AllOf return a new future which will be completed with all values of futures in order.
+// The new future will be completed with the first error if any future failed,
+// and the other futures will be canceled.
+func AllOf[T any](ctx context.Context, futures ...Future[T]) Future[[]T] {
+	result := newAggregateFuture[[]T](ctx, futures)
+	if len(futures) == 0 {
+		result.completeValue([]T{})
+		return result
+	}
+
+	values := make([]T, len(futures))
+	var remaining atomic.Int32
+	remaining.Store(int32(len(futures)))
+
+	for i, f := range futures {
+		whenComplete(f, func() {
+			val, err := f.Get(context.Background())
+			if err != nil {
+				result.completeError(err)
+				return
+			}
+			values[i] = val
+			if remaining.Add(-1) == 0 {
+				result.completeValue(values)
+			}
+		})
+	}
+	return result
+}
+
+// AnyOf return a new future which will be completed with the value of the first succeeded future,
+// and the other futures will be canceled.
+// The new future will be completed with all errors joined if all futures failed.
+func AnyOf[T any](ctx context.Context, futures ...Future[T]) Future[T] {
+	result := newAggregateFuture[T](ctx, futures)
+	if len(futures) == 0 {
+		result.completeError(ErrEmptyFutures)
+		return result
+	}
+
+	var (
+		locker    sync.Mutex
+		errs      = make([]error, len(futures))
+		remaining = len(futures)
+	)
+
+	for i, f := range futures {
+		whenComplete(f, func() {
+			val, err := f.Get(context.Background())
+			if err == nil {
+				result.completeValue(val)
+				return
+			}
+
+			locker.Lock()
+			errs[i] = err
+			remaining--
+			done := remaining == 0
+			locker.Unlock()
+
+			if done {
+				result.completeError(errors.Join(errs...))
+			}
+		})
+	}
+	return result
+}
+
+// Race return a new future which will be completed with the result of the first completed future,
+// no matter succeeded or failed, and the other futures will be canceled.
+func Race[T any](ctx context.Context, futures ...Future[T]) Future[T] {
+	result := newAggregateFuture[T](ctx, futures)
+	if len(futures) == 0 {
+		result.completeError(ErrEmptyFutures)
+		return result
+	}
+
+	for _, f := range futures {
+		whenComplete(f, func() {
+			result.complete(f.Get(context.Background()))
+		})
+	}
+	return result
+}
+
+// AllSettled return a new future which will be completed with the results of all futures in order
+// after all futures completed, no matter succeeded or failed.
+func AllSettled[T any](ctx context.Context, futures ...Future[T]) Future[[]Result[T]] {
+	result := newAggregateFuture[[]Result[T]](ctx, futures)
+	if len(futures) == 0 {
+		result.completeValue([]Result[T]{})
+		return result
+	}
+
+	results := make([]Result[T], len(futures))
+	var remaining atomic.Int32
+	remaining.Store(int32(len(futures)))
+
+	for i, f := range futures {
+		whenComplete(f, func() {
+			val, err := f.Get(context.Background())
+			results[i] = Result[T]{Value: val, Err: err}
+			if remaining.Add(-1) == 0 {
+				result.completeValue(results)
+			}
+		})
+	}
+	return result
+}
+
+// newAggregateFuture create a future which will be completed with ctx.Err() if ctx done,
+// and will cancel all futures not completed yet after completed.
+func newAggregateFuture[R, T any](ctx context.Context, futures []Future[T]) *FutureTask[R] {
+	result := newFutureTask[R]()
+
+	stop := context.AfterFunc(ctx, func() {
+		result.completeError(ctx.Err())
+	})
+
+	result.onComplete(func() {
+		stop()
+		for _, f := range futures {
+			f.Cancel()
+		}
+	})
+	return result
+}
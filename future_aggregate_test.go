@@ -0,0 +1,123 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func submitAfter[T any](t *testing.T, service ExecutorService[T], delay time.Duration, val T, err error) Future[T] {
+	f, e := service.SubmitFunc(func(ctx context.Context) (T, error) {
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-time.After(delay):
+			return val, err
+		}
+	})
+	require.NoError(t, e)
+	return f
+}
+
+func TestAllOf(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	t.Run("all succeed", func(t *testing.T) {
+		got, err := AllOf(context.Background(),
+			submitAfter(t, service, 30*time.Millisecond, 1, nil),
+			submitAfter(t, service, 10*time.Millisecond, 2, nil),
+			submitAfter(t, service, 20*time.Millisecond, 3, nil),
+		).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2, 3}, got)
+	})
+
+	t.Run("one failed", func(t *testing.T) {
+		targetErr := errors.New("failed")
+		slow := submitAfter(t, service, time.Second, 1, nil)
+		_, err := AllOf(context.Background(),
+			slow,
+			submitAfter(t, service, 10*time.Millisecond, 2, targetErr),
+		).Get(context.Background())
+		require.ErrorIs(t, err, targetErr)
+		require.Eventually(t, slow.Canceled, time.Second, time.Millisecond)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		slow := submitAfter(t, service, time.Second, 1, nil)
+		_, err := AllOf(ctx, slow).Get(context.Background())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Eventually(t, slow.Canceled, time.Second, time.Millisecond)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := AllOf[int](context.Background()).Get(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+}
+
+func TestAnyOf(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	t.Run("first success", func(t *testing.T) {
+		slow := submitAfter(t, service, time.Second, 1, nil)
+		got, err := AnyOf(context.Background(),
+			slow,
+			submitAfter(t, service, 10*time.Millisecond, 2, errors.New("failed")),
+			submitAfter(t, service, 20*time.Millisecond, 3, nil),
+		).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, got)
+		require.Eventually(t, slow.Canceled, time.Second, time.Millisecond)
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		err1 := errors.New("failed1")
+		err2 := errors.New("failed2")
+		_, err := AnyOf(context.Background(),
+			submitAfter(t, service, 10*time.Millisecond, 1, err1),
+			submitAfter(t, service, 20*time.Millisecond, 2, err2),
+		).Get(context.Background())
+		require.ErrorIs(t, err, err1)
+		require.ErrorIs(t, err, err2)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := AnyOf[int](context.Background()).Get(context.Background())
+		require.ErrorIs(t, err, ErrEmptyFutures)
+	})
+}
+
+func TestRace(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	targetErr := errors.New("failed")
+	slow := submitAfter(t, service, time.Second, 1, nil)
+	_, err := Race(context.Background(),
+		slow,
+		submitAfter(t, service, 10*time.Millisecond, 2, targetErr),
+	).Get(context.Background())
+	require.ErrorIs(t, err, targetErr)
+	require.Eventually(t, slow.Canceled, time.Second, time.Millisecond)
+}
+
+func TestAllSettled(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	targetErr := errors.New("failed")
+	got, err := AllSettled(context.Background(),
+		submitAfter(t, service, 20*time.Millisecond, 1, nil),
+		submitAfter(t, service, 10*time.Millisecond, 2, targetErr),
+	).Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, Result[int]{Value: 1}, got[0])
+	require.ErrorIs(t, got[1].Err, targetErr)
+}
@@ -0,0 +1,145 @@
+package executors
+
+import (
+	"context"
+)
+
+type completionListenable interface {
+	onComplete(listener func())
+}
+
+// callbackRunner run fn, return error if fn can not be run.
+type callbackRunner func(fn func()) error
+
+func runSync(fn func()) error {
+	fn()
+	return nil
+}
+
+// callbackExecutorOf return the default callback executor of f, nil if no.
+func callbackExecutorOf[T any](f Future[T]) Executor {
+	if task, ok := f.(*FutureTask[T]); ok {
+		return task.callbackExecutor
+	}
+	return nil
+}
+
+// asyncRunner return a runner which run fn by executor,
+// or the callback executor of f if executor is nil,
+// or a new goroutine if both nil.
+func asyncRunner[T any](f Future[T], executor Executor) callbackRunner {
+	if executor == nil {
+		executor = callbackExecutorOf(f)
+	}
+	if executor == nil {
+		return func(fn func()) error {
+			go fn()
+			return nil
+		}
+	}
+	return func(fn func()) error {
+		return executor.Execute(RunnableFunc(func(ctx context.Context) {
+			fn()
+		}))
+	}
+}
+
+// whenComplete call listener after f completed.
+// Will wait in a new goroutine if f is not implemented by FutureTask.
+func whenComplete[T any](f Future[T], listener func()) {
+	if l, ok := f.(completionListenable); ok {
+		l.onComplete(listener)
+		return
+	}
+	go func() {
+		_, _ = f.Get(context.Background())
+		listener()
+	}()
+}
+
+// Map return a new future which will be completed with the result of fn applied to the value of f.
+// The new future will be completed with the error of f if f failed,
+// and cancel the new future will cancel f too.
+func Map[T, R any](f Future[T], fn func(val T) (R, error)) Future[R] {
+	return mapWith(f, fn, runSync)
+}
+
+// MapAsync like Map, but fn will be executed by executor.
+// Will use the callback executor of f if executor is nil,
+// and run in a new goroutine if no callback executor either.
+func MapAsync[T, R any](f Future[T], fn func(val T) (R, error), executor Executor) Future[R] {
+	return mapWith(f, fn, asyncRunner(f, executor))
+}
+
+func mapWith[T, R any](f Future[T], fn func(val T) (R, error), run callbackRunner) Future[R] {
+	return compose(f, func(val T, result *FutureTask[R]) {
+		result.complete(fn(val))
+	}, run)
+}
+
+// FlatMap return a new future which will be completed with the result of the future returned by fn.
+// The new future will be completed with the error of f if f failed,
+// and cancel the new future will cancel f and the future returned by fn too.
+func FlatMap[T, R any](f Future[T], fn func(val T) (Future[R], error)) Future[R] {
+	return flatMapWith(f, fn, runSync)
+}
+
+// FlatMapAsync like FlatMap, but fn will be executed by executor.
+// Will use the callback executor of f if executor is nil,
+// and run in a new goroutine if no callback executor either.
+func FlatMapAsync[T, R any](f Future[T], fn func(val T) (Future[R], error), executor Executor) Future[R] {
+	return flatMapWith(f, fn, asyncRunner(f, executor))
+}
+
+func flatMapWith[T, R any](f Future[T], fn func(val T) (Future[R], error), run callbackRunner) Future[R] {
+	return compose(f, func(val T, result *FutureTask[R]) {
+		next, err := fn(val)
+		if err != nil {
+			result.completeError(err)
+			return
+		}
+		result.onComplete(func() {
+			if result.Canceled() {
+				next.Cancel()
+			}
+		})
+		whenComplete(next, func() {
+			result.complete(next.Get(context.Background()))
+		})
+	}, run)
+}
+
+// ThenCompose like FlatMap, same as CompletableFuture.thenCompose in Java.
+func ThenCompose[T, R any](f Future[T], fn func(val T) Future[R]) Future[R] {
+	return FlatMap(f, func(val T) (Future[R], error) {
+		return fn(val), nil
+	})
+}
+
+func compose[T, R any](f Future[T], apply func(val T, result *FutureTask[R]), run callbackRunner) Future[R] {
+	result := newFutureTask[R]()
+	result.callbackExecutor = callbackExecutorOf(f)
+	result.cancelFunc = func() {
+		f.Cancel()
+	}
+
+	whenComplete(f, func() {
+		val, err := f.Get(context.Background())
+		if err != nil {
+			result.completeError(err)
+			return
+		}
+		err = run(func() {
+			defer func() {
+				if cause := recover(); cause != nil {
+					result.completeError(newErrPanic(cause, nil))
+				}
+			}()
+			apply(val, result)
+		})
+		if err != nil {
+			result.completeError(err)
+		}
+	})
+	return result
+}
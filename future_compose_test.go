@@ -0,0 +1,98 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	t.Run("map value", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 10, nil
+		})
+		require.NoError(t, err)
+
+		got, err := Map(f, func(val int) (string, error) {
+			return strconv.Itoa(val * 2), nil
+		}).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "20", got)
+	})
+
+	t.Run("map error", func(t *testing.T) {
+		targetErr := errors.New("call error")
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 0, targetErr
+		})
+		require.NoError(t, err)
+
+		mapped := false
+		_, err = Map(f, func(val int) (string, error) {
+			mapped = true
+			return strconv.Itoa(val), nil
+		}).Get(context.Background())
+		require.ErrorIs(t, err, targetErr)
+		require.False(t, mapped)
+	})
+
+	t.Run("map async", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 10, nil
+		})
+		require.NoError(t, err)
+
+		got, err := MapAsync(f, func(val int) (int, error) {
+			return val + 1, nil
+		}, NewPoolExecutor()).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 11, got)
+	})
+
+	t.Run("cancel source", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		require.NoError(t, err)
+
+		mapped := Map(f, func(val int) (int, error) {
+			return val, nil
+		})
+		require.True(t, mapped.Cancel())
+
+		_, err = f.Get(context.Background())
+		require.ErrorIs(t, err, ErrFutureCanceled)
+	})
+}
+
+func TestFlatMap(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+		return 10, nil
+	})
+	require.NoError(t, err)
+
+	got, err := ThenCompose(f, func(val int) Future[int] {
+		next, _ := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return val * 3, nil
+		})
+		return next
+	}).Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 30, got)
+
+	targetErr := errors.New("flat map error")
+	_, err = FlatMap(f, func(val int) (Future[int], error) {
+		return nil, targetErr
+	}).Get(context.Background())
+	require.ErrorIs(t, err, targetErr)
+}
@@ -3,7 +3,11 @@ package executors
 import (
 	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
+	"time"
+
+	gxtime "github.com/dubbogo/timer"
 )
 
 type ErrInvalidState struct {
@@ -27,6 +31,9 @@ func (e ErrInvalidState) Error() string {
 	}
 }
 
+// timeoutTimerWheel the timer wheel shared by the timeouts of all futures.
+var timeoutTimerWheel = sync.OnceValue(gxtime.NewTimerWheel)
+
 const (
 	_StateNew uint32 = iota
 	_StateCompleting
@@ -42,8 +49,11 @@ type FutureTask[T any] struct {
 	closeCh    chan struct{}
 	state      uint32
 	cancelFunc context.CancelFunc
-	thenFunc   ThenFunction[T]
-	catchFunc  CatchFunction
+	locker     sync.Mutex
+	listeners  []func()
+
+	// callbackExecutor the default executor to run async callbacks
+	callbackExecutor Executor
 }
 
 func NewFutureTask[T any](callable Callable[T]) *FutureTask[T] {
@@ -54,19 +64,37 @@ func NewFutureTask[T any](callable Callable[T]) *FutureTask[T] {
 	}
 }
 
+// newFutureTask create a future task without callable, should be completed manually.
+func newFutureTask[T any]() *FutureTask[T] {
+	return NewFutureTask[T](nil)
+}
+
 // Run implement runnable
 func (f *FutureTask[T]) Run(ctx context.Context) {
-	if f.state != _StateNew {
+	if atomic.LoadUint32(&f.state) != _StateNew {
 		return
 	}
 
-	ctx, f.cancelFunc = context.WithCancel(ctx)
-	val, err := f.callable.Call(ctx)
-	if err != nil {
-		f.completeError(err)
-		return
+	defer func() {
+		if cause := recover(); cause != nil {
+			err := newErrPanic(cause, f)
+			f.completeError(err)
+			// panic again to notify the ErrorHandler of the executor
+			panic(err)
+		}
+	}()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	f.locker.Lock()
+	f.cancelFunc = cancelFunc
+	f.locker.Unlock()
+	if f.Completed() {
+		// canceled or timeout before cancelFunc set
+		cancelFunc()
 	}
-	f.completeValue(val)
+
+	val, err := f.callable.Call(ctx)
+	f.complete(val, err)
 }
 
 func (f *FutureTask[T]) Get(ctx context.Context) (T, error) {
@@ -82,20 +110,108 @@ func (f *FutureTask[T]) Get(ctx context.Context) (T, error) {
 	}
 }
 
-func (f *FutureTask[T]) Then(thenFunc ThenFunction[T]) NotThenableFuture[T] {
-	f.thenFunc = thenFunc
-	if f.Completed() {
-		f.postComplete()
-	}
+func (f *FutureTask[T]) Then(thenFunc ThenFunction[T]) Future[T] {
+	return f.derive(thenCallback(thenFunc), runSync)
+}
+
+func (f *FutureTask[T]) ThenAsync(thenFunc ThenFunction[T], executor Executor) Future[T] {
+	return f.derive(thenCallback(thenFunc), asyncRunner[T](f, executor))
+}
+
+func (f *FutureTask[T]) Catch(catchFunc CatchFunction) Future[T] {
+	return f.derive(catchCallback[T](catchFunc), runSync)
+}
+
+func (f *FutureTask[T]) CatchAsync(catchFunc CatchFunction, executor Executor) Future[T] {
+	return f.derive(catchCallback[T](catchFunc), asyncRunner[T](f, executor))
+}
+
+func (f *FutureTask[T]) Finally(finallyFunc FinallyFunction) Future[T] {
+	return f.derive(func(val T, err error) error {
+		finallyFunc()
+		return nil
+	}, runSync)
+}
+
+func (f *FutureTask[T]) OnComplete(completeFunc CompleteFunction[T]) Future[T] {
+	return f.derive(func(val T, err error) error {
+		completeFunc(val, err)
+		return nil
+	}, runSync)
+}
+
+func (f *FutureTask[T]) OrTimeout(timeout time.Duration) Future[T] {
+	f.completeOnTimeout(timeout, func() bool {
+		return f.completeError(ErrFutureTimeout)
+	})
 	return f
 }
 
-func (f *FutureTask[T]) Catch(catchFunc CatchFunction) NotChainableFuture[T] {
-	f.catchFunc = catchFunc
+func (f *FutureTask[T]) CompleteOnTimeout(val T, timeout time.Duration) Future[T] {
+	f.completeOnTimeout(timeout, func() bool {
+		return f.completeValue(val)
+	})
+	return f
+}
+
+func (f *FutureTask[T]) completeOnTimeout(timeout time.Duration, complete func() bool) {
 	if f.Completed() {
-		f.postComplete()
+		return
 	}
-	return f
+	timer := timeoutTimerWheel().AfterFunc(timeout, func() {
+		if complete() {
+			f.cancelCallable()
+		}
+	})
+	f.onComplete(timer.Stop)
+}
+
+func thenCallback[T any](thenFunc ThenFunction[T]) func(val T, err error) error {
+	return func(val T, err error) error {
+		if err != nil {
+			return nil
+		}
+		return thenFunc(val)
+	}
+}
+
+func catchCallback[T any](catchFunc CatchFunction) func(val T, err error) error {
+	return func(val T, err error) error {
+		if err != nil {
+			catchFunc(err)
+		}
+		return nil
+	}
+}
+
+// derive return a new future which will be completed with the result of f after callback called by run,
+// or the error returned by callback.
+func (f *FutureTask[T]) derive(callback func(val T, err error) error, run callbackRunner) Future[T] {
+	next := newFutureTask[T]()
+	next.callbackExecutor = f.callbackExecutor
+	next.cancelFunc = func() {
+		f.Cancel()
+	}
+
+	f.onComplete(func() {
+		err := run(func() {
+			defer func() {
+				if cause := recover(); cause != nil {
+					next.completeError(newErrPanic(cause, nil))
+				}
+			}()
+			val, err := f.report(atomic.LoadUint32(&f.state))
+			if e := callback(val, err); e != nil {
+				next.completeError(e)
+				return
+			}
+			next.complete(val, err)
+		})
+		if err != nil {
+			next.completeError(err)
+		}
+	})
+	return next
 }
 
 func (f *FutureTask[T]) report(state uint32) (T, error) {
@@ -111,43 +227,63 @@ func (f *FutureTask[T]) report(state uint32) (T, error) {
 	}
 }
 
-func (f *FutureTask[T]) completeValue(val T) {
+func (f *FutureTask[T]) complete(val T, err error) bool {
+	if err != nil {
+		return f.completeError(err)
+	}
+	return f.completeValue(val)
+}
+
+func (f *FutureTask[T]) completeValue(val T) bool {
 	state := &f.state
 	if atomic.CompareAndSwapUint32(state, _StateNew, _StateCompleting) {
 		f.val = val
 		atomic.StoreUint32(state, _StateNormal)
 		close(f.closeCh)
-		f.postComplete()
+		f.notifyListeners()
+		return true
 	}
+	return false
 }
 
-func (f *FutureTask[T]) completeError(err error) {
+func (f *FutureTask[T]) completeError(err error) bool {
 	state := &f.state
 	if atomic.CompareAndSwapUint32(state, _StateNew, _StateCompleting) {
 		f.err = err
 		atomic.StoreUint32(state, _StateError)
 		close(f.closeCh)
-		f.postComplete()
+		f.notifyListeners()
+		return true
 	}
+	return false
 }
 
-func (f *FutureTask[T]) postComplete() {
-	if f.CompletedError() {
-		if f.catchFunc != nil {
-			f.catchFunc(f.err)
-		}
-	} else {
-		if f.thenFunc != nil {
-			err := f.thenFunc(f.val)
-			if err != nil && f.catchFunc != nil {
-				f.catchFunc(err)
-			}
-		}
+// onComplete register a listener which will be called exactly once after the future completed.
+// Will call the listener immediately if the future completed already.
+func (f *FutureTask[T]) onComplete(listener func()) {
+	f.locker.Lock()
+	if !f.Completed() {
+		f.listeners = append(f.listeners, listener)
+		f.locker.Unlock()
+		return
+	}
+	f.locker.Unlock()
+	listener()
+}
+
+func (f *FutureTask[T]) notifyListeners() {
+	f.locker.Lock()
+	listeners := f.listeners
+	f.listeners = nil
+	f.locker.Unlock()
+
+	for _, listener := range listeners {
+		listener()
 	}
 }
 
 func (f *FutureTask[T]) Cancel() bool {
-	if f.state != _StateNew {
+	if atomic.LoadUint32(&f.state) != _StateNew {
 		return false
 	}
 	ok := atomic.CompareAndSwapUint32(&f.state, _StateNew, _StateCanceled)
@@ -155,15 +291,31 @@ func (f *FutureTask[T]) Cancel() bool {
 		f.cancelCallable()
 		f.err = ErrFutureCanceled
 		close(f.closeCh)
-		f.postComplete()
+		f.notifyListeners()
 		return true
 	}
 	return false
 }
 
 func (f *FutureTask[T]) cancelCallable() {
-	if f.cancelFunc != nil {
-		f.cancelFunc()
+	f.locker.Lock()
+	cancelFunc := f.cancelFunc
+	f.locker.Unlock()
+
+	if cancelFunc != nil {
+		cancelFunc()
+	}
+}
+
+// failure return the error if completed with error, nil otherwise.
+func (f *FutureTask[T]) failure() error {
+	switch atomic.LoadUint32(&f.state) {
+	case _StateCanceled:
+		return ErrFutureCanceled
+	case _StateError:
+		return f.err
+	default:
+		return nil
 	}
 }
 
@@ -181,3 +333,8 @@ func (f *FutureTask[T]) CompletedError() bool {
 	state := atomic.LoadUint32(&f.state)
 	return state != _StateNormal
 }
+
+// completable the future can be completed with error by the executors which drop the task, like FutureTask.
+type completable interface {
+	completeError(err error) bool
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -13,13 +16,13 @@ func TestFutureTask_Then(t *testing.T) {
 	service := NewPoolExecutorService[Person](WithMaxConcurrent(10))
 
 	name1 := ""
-	ch1 := make(chan struct{})
+	ch1 := make(chan struct{}, 1)
 
 	name2 := ""
-	ch2 := make(chan struct{})
+	ch2 := make(chan struct{}, 1)
 
 	name3 := ""
-	ch3 := make(chan struct{})
+	ch3 := make(chan struct{}, 1)
 
 	t.Run("success then", func(t *testing.T) {
 		callable := CallableFunc[Person](func(ctx context.Context) (Person, error) {
@@ -92,10 +95,10 @@ func TestFutureTask_Catch(t *testing.T) {
 	service := NewPoolExecutorService[Person](WithMaxConcurrent(10))
 
 	thenName1 := ""
-	ch1 := make(chan struct{})
+	ch1 := make(chan struct{}, 1)
 
 	thenName2 := ""
-	ch2 := make(chan struct{})
+	ch2 := make(chan struct{}, 1)
 
 	t.Run("success then", func(t *testing.T) {
 		callable := CallableFunc[Person](func(ctx context.Context) (Person, error) {
@@ -143,3 +146,222 @@ func TestFutureTask_Catch(t *testing.T) {
 	require.Equal(t, "", thenName1)
 	require.Equal(t, "future2", thenName2)
 }
+
+func TestFutureTask_Listeners(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	t.Run("every callback called exactly once", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			time.Sleep(time.Millisecond)
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		var (
+			counter atomic.Int32
+			wg      sync.WaitGroup
+		)
+		futures := make([]Future[int], 100)
+		for i := range futures {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				futures[i] = f.Then(func(val int) error {
+					counter.Add(1)
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+
+		_, err = AllOf(context.Background(), futures...).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int32(100), counter.Load())
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		var steps []string
+		targetErr := errors.New("then error")
+		_, err = f.Then(func(val int) error {
+			steps = append(steps, "then1")
+			return nil
+		}).Then(func(val int) error {
+			steps = append(steps, "then2")
+			return targetErr
+		}).Then(func(val int) error {
+			steps = append(steps, "then3")
+			return nil
+		}).Catch(func(err error) {
+			steps = append(steps, "catch:"+err.Error())
+		}).OnComplete(func(val int, err error) {
+			steps = append(steps, "complete")
+		}).Finally(func() {
+			steps = append(steps, "finally")
+		}).Get(context.Background())
+
+		require.ErrorIs(t, err, targetErr)
+		require.Equal(t, []string{"then1", "then2", "catch:then error", "complete", "finally"}, steps)
+	})
+
+	t.Run("callback after completed", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+		_, _ = f.Get(context.Background())
+
+		var counter int
+		f.Then(func(val int) error {
+			counter++
+			return nil
+		})
+		f.Catch(func(err error) {
+			counter += 10
+		})
+		require.Equal(t, 1, counter)
+	})
+}
+
+type countingExecutor struct {
+	Executor
+	counter atomic.Int32
+}
+
+func (e *countingExecutor) Execute(r Runnable) error {
+	e.counter.Add(1)
+	return e.Executor.Execute(r)
+}
+
+func TestFutureTask_ThenAsync(t *testing.T) {
+	callbackExecutor := &countingExecutor{Executor: NewPoolExecutor(WithMaxConcurrent(1))}
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10), WithCallbackExecutor(callbackExecutor))
+
+	t.Run("default callback executor", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		got := 0
+		_, err = f.ThenAsync(func(val int) error {
+			got = val
+			return nil
+		}, nil).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, got)
+		require.Equal(t, int32(1), callbackExecutor.counter.Load())
+	})
+
+	t.Run("specified executor", func(t *testing.T) {
+		executor := &countingExecutor{Executor: NewPoolExecutor(WithMaxConcurrent(1))}
+		targetErr := errors.New("call error")
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 0, targetErr
+		})
+		require.NoError(t, err)
+
+		var caught error
+		_, err = f.CatchAsync(func(err error) {
+			caught = err
+		}, executor).Get(context.Background())
+		require.ErrorIs(t, err, targetErr)
+		require.ErrorIs(t, caught, targetErr)
+		require.Equal(t, int32(1), executor.counter.Load())
+	})
+
+	t.Run("new goroutine", func(t *testing.T) {
+		p := NewPromise[int]()
+		p.Resolve(1)
+
+		ch := make(chan struct{})
+		next := p.Future().ThenAsync(func(val int) error {
+			<-ch
+			return nil
+		}, nil)
+		require.False(t, next.Completed())
+		close(ch)
+
+		_, err := next.Get(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestFutureTask_OrTimeout(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	t.Run("timeout", func(t *testing.T) {
+		canceled := make(chan struct{})
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			close(canceled)
+			return 0, ctx.Err()
+		})
+		require.NoError(t, err)
+
+		_, err = f.OrTimeout(50 * time.Millisecond).Get(context.Background())
+		require.ErrorIs(t, err, ErrFutureTimeout)
+		<-canceled
+	})
+
+	t.Run("completed in time", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		got, err := f.OrTimeout(time.Second).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, got)
+	})
+}
+
+func TestFutureTask_CompleteOnTimeout(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	require.NoError(t, err)
+
+	got, err := f.CompleteOnTimeout(10, 50*time.Millisecond).Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 10, got)
+}
+
+func TestFutureTask_Panic(t *testing.T) {
+	caught := make(chan error, 1)
+	service := NewPoolExecutorService[int](
+		WithMaxConcurrent(10),
+		WithErrorHandler(ErrorHandlerFunc(func(runnable Runnable, e error) {
+			caught <- e
+		})))
+
+	targetErr := errors.New("callable panic")
+	f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+		panic(targetErr)
+	})
+	require.NoError(t, err)
+
+	catchCh := make(chan error, 1)
+	f.Catch(func(err error) {
+		catchCh <- err
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = f.Get(ctx)
+	require.ErrorIs(t, err, targetErr)
+
+	var errPanic ErrPanic
+	require.ErrorAs(t, err, &errPanic)
+	require.Contains(t, string(errPanic.Stack), "TestFutureTask_Panic")
+
+	require.ErrorIs(t, <-catchCh, targetErr)
+	require.ErrorIs(t, <-caught, targetErr)
+}
@@ -0,0 +1,246 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrKeyQueueFull = errors.New("key queue full")
+)
+
+// KeyedExecutor run the tasks with the same key serially in submission order,
+// and the tasks with different keys in parallel by the underlying executor.
+//
+// The tasks of a key will be run one by one in the same worker of the underlying executor,
+// the key will be removed after all its tasks finished.
+//
+// The worker of a key is submitted to PoolExecutor without its RejectionHandler,
+// so a key will never be discarded silently. If the worker can not be started or is evicted from the queue,
+// the waiting tasks of the key will be dropped, and the futures completed with the error.
+type KeyedExecutor struct {
+	executor Executor
+	// maxQueued the max count of waiting tasks per key, unlimited if 0
+	maxQueued int
+
+	locker   sync.Mutex
+	keys     map[string]*keyQueue
+	shutdown bool
+	// drained closed after shutdown and all keys drained
+	drained chan struct{}
+}
+
+// keyQueue the waiting tasks of a key.
+type keyQueue struct {
+	tasks []Runnable
+}
+
+// NewKeyedExecutor create a KeyedExecutor run the tasks by executor,
+// maxQueuedPerKey the max count of waiting tasks per key, unlimited if 0.
+func NewKeyedExecutor(executor Executor, maxQueuedPerKey int) *KeyedExecutor {
+	return &KeyedExecutor{
+		executor:  executor,
+		maxQueued: maxQueuedPerKey,
+		keys:      map[string]*keyQueue{},
+		drained:   make(chan struct{}),
+	}
+}
+
+// ExecuteKeyed execute the runnable after all the submitted tasks with the same key finished.
+// Will return ErrKeyQueueFull if too many tasks waiting for the key,
+// or the error of the underlying executor if failed to start the key, like ErrRejectedExecution.
+func (e *KeyedExecutor) ExecuteKeyed(key string, r Runnable) error {
+	e.locker.Lock()
+	if e.shutdown || e.executor.IsShutdown() {
+		e.locker.Unlock()
+		return ErrShutdown
+	}
+	if q, ok := e.keys[key]; ok {
+		defer e.locker.Unlock()
+
+		if e.maxQueued > 0 && len(q.tasks) >= e.maxQueued {
+			return ErrKeyQueueFull
+		}
+		q.tasks = append(q.tasks, r)
+		return nil
+	}
+	q := &keyQueue{}
+	e.keys[key] = q
+	e.locker.Unlock()
+
+	err := e.start(key, q, r)
+	if err != nil {
+		// the tasks queued after r should still run
+		e.resume(key, q)
+	}
+	return err
+}
+
+// ExecuteKeyedFunc execute the func after all the submitted tasks with the same key finished.
+func (e *KeyedExecutor) ExecuteKeyedFunc(key string, fn func(ctx context.Context)) error {
+	return e.ExecuteKeyed(key, RunnableFunc(fn))
+}
+
+// SubmitKeyed submit the callable after all the submitted tasks with the same key finished.
+func SubmitKeyed[T any](executor *KeyedExecutor, key string, callable Callable[T]) (Future[T], error) {
+	f := NewFutureTask[T](callable)
+	err := executor.ExecuteKeyed(key, f)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SubmitKeyedFunc submit the func after all the submitted tasks with the same key finished.
+func SubmitKeyedFunc[T any](executor *KeyedExecutor, key string, fn func(ctx context.Context) (T, error)) (Future[T], error) {
+	return SubmitKeyed[T](executor, key, CallableFunc[T](fn))
+}
+
+// keyRunner run the tasks of a key in a worker of the underlying executor.
+type keyRunner struct {
+	executor *KeyedExecutor
+	key      string
+	queue    *keyQueue
+	first    Runnable
+	// done set after started or canceled
+	done atomic.Bool
+}
+
+func (r *keyRunner) Run(ctx context.Context) {
+	if r.done.Swap(true) {
+		return
+	}
+	r.executor.runKey(ctx, r.key, r.queue, r.first)
+}
+
+// Cancel will be called if evicted from the queue, like DiscardOldestPolicy.
+func (r *keyRunner) Cancel() bool {
+	if r.done.Swap(true) {
+		return false
+	}
+	r.executor.drop(r.key, r.queue, r.first, ErrRejectedExecution)
+	return true
+}
+
+// start submit the runner of the key to the underlying executor,
+// bypass the RejectionHandler of PoolExecutor, so the runner will never be discarded silently.
+func (e *KeyedExecutor) start(key string, q *keyQueue, r Runnable) error {
+	runner := &keyRunner{executor: e, key: key, queue: q, first: r}
+	if executor, ok := e.executor.(queueAccessor); ok {
+		return executor.offer(context.Background(), runner, false)
+	}
+	return e.executor.Execute(runner)
+}
+
+// runKey run r and then the waiting tasks of the key until no task left.
+func (e *KeyedExecutor) runKey(ctx context.Context, key string, q *keyQueue, r Runnable) {
+	for ok := r != nil; ok; r, ok = e.next(key, q) {
+		e.runTask(ctx, key, q, r)
+	}
+}
+
+// runTask run the task, will resume the key in a new worker if panicked.
+func (e *KeyedExecutor) runTask(ctx context.Context, key string, q *keyQueue, r Runnable) {
+	defer func() {
+		if cause := recover(); cause != nil {
+			e.resume(key, q)
+			// the remaining tasks of the key resumed in a new worker already,
+			// re-panic so this worker reports the panic to the ErrorHandler of the underlying executor
+			panic(cause)
+		}
+	}()
+	r.Run(ctx)
+}
+
+// resume run the waiting tasks of the key in a new worker.
+func (e *KeyedExecutor) resume(key string, q *keyQueue) {
+	r, ok := e.next(key, q)
+	if !ok {
+		return
+	}
+	if err := e.start(key, q, r); err != nil {
+		// can not run the waiting tasks anymore, drop them to not block the key forever
+		e.drop(key, q, r, err)
+	}
+}
+
+// drop drop r and the waiting tasks of the key, the futures will be completed with err.
+func (e *KeyedExecutor) drop(key string, q *keyQueue, r Runnable, err error) {
+	e.locker.Lock()
+	tasks := append([]Runnable{r}, q.tasks...)
+	q.tasks = nil
+	if e.keys[key] == q {
+		delete(e.keys, key)
+	}
+	e.tryDrained()
+	e.locker.Unlock()
+
+	for _, task := range tasks {
+		if f, ok := task.(completable); ok {
+			f.completeError(err)
+		}
+	}
+}
+
+// next pop the next waiting task of the key, will remove the key if no task left.
+func (e *KeyedExecutor) next(key string, q *keyQueue) (Runnable, bool) {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+
+	if len(q.tasks) == 0 {
+		if e.keys[key] == q {
+			delete(e.keys, key)
+		}
+		e.tryDrained()
+		return nil, false
+	}
+	r := q.tasks[0]
+	q.tasks[0] = nil
+	q.tasks = q.tasks[1:]
+	return r, true
+}
+
+// tryDrained close drained if shutdown and all keys drained, should be called with lock.
+func (e *KeyedExecutor) tryDrained() {
+	if !e.shutdown || len(e.keys) > 0 {
+		return
+	}
+	select {
+	case <-e.drained:
+	default:
+		close(e.drained)
+	}
+}
+
+// Shutdown reject new tasks, wait all the waiting tasks of every key finished,
+// and then shutdown the underlying executor.
+// Will not wait the keys dropped by ShutdownNow of the underlying executor.
+func (e *KeyedExecutor) Shutdown(ctx context.Context) error {
+	e.locker.Lock()
+	e.shutdown = true
+	e.tryDrained()
+	e.locker.Unlock()
+
+	terminated := make(chan struct{})
+	go func() {
+		_ = e.executor.AwaitTermination(ctx)
+		close(terminated)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-e.drained:
+	case <-terminated:
+	}
+	return e.executor.Shutdown(ctx)
+}
+
+// Keys return the count of keys with running or waiting tasks.
+func (e *KeyedExecutor) Keys() int {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+
+	return len(e.keys)
+}
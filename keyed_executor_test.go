@@ -0,0 +1,186 @@
+package executors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedExecutor(t *testing.T) {
+	t.Run("serial per key", func(t *testing.T) {
+		executor := NewKeyedExecutor(NewPoolExecutor(WithMaxConcurrent(4)), 0)
+
+		var (
+			locker  sync.Mutex
+			orders  = map[string][]int{}
+			running = map[string]*atomic.Int32{}
+			overlap atomic.Bool
+		)
+		keys := []string{"a", "b", "c"}
+		for _, key := range keys {
+			running[key] = &atomic.Int32{}
+		}
+		for i := range 50 {
+			for _, key := range keys {
+				require.NoError(t, executor.ExecuteKeyedFunc(key, func(ctx context.Context) {
+					if running[key].Add(1) > 1 {
+						overlap.Store(true)
+					}
+					defer running[key].Add(-1)
+
+					locker.Lock()
+					orders[key] = append(orders[key], i)
+					locker.Unlock()
+				}))
+			}
+		}
+		require.NoError(t, executor.Shutdown(context.Background()))
+		require.False(t, overlap.Load())
+
+		for _, key := range keys {
+			require.Len(t, orders[key], 50)
+			for i, got := range orders[key] {
+				require.Equal(t, i, got)
+			}
+		}
+		require.Equal(t, 0, executor.Keys())
+		require.ErrorIs(t, executor.ExecuteKeyedFunc("a", func(ctx context.Context) {}), ErrShutdown)
+	})
+
+	t.Run("parallel across keys", func(t *testing.T) {
+		executor := NewKeyedExecutor(NewPoolExecutor(WithMaxConcurrent(2)), 0)
+		defer executor.Shutdown(context.Background())
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		for _, key := range []string{"a", "b"} {
+			require.NoError(t, executor.ExecuteKeyedFunc(key, func(ctx context.Context) {
+				wg.Done()
+				// both keys must be running at the same time
+				wg.Wait()
+			}))
+		}
+		wg.Wait()
+	})
+
+	t.Run("key queue full", func(t *testing.T) {
+		executor := NewKeyedExecutor(NewPoolExecutor(), 1)
+
+		block := make(chan struct{})
+		require.NoError(t, executor.ExecuteKeyedFunc("a", func(ctx context.Context) { <-block }))
+		require.NoError(t, executor.ExecuteKeyedFunc("a", func(ctx context.Context) {}))
+		require.ErrorIs(t, executor.ExecuteKeyedFunc("a", func(ctx context.Context) {}), ErrKeyQueueFull)
+		require.NoError(t, executor.ExecuteKeyedFunc("b", func(ctx context.Context) {}))
+
+		close(block)
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		executor := NewKeyedExecutor(NewPoolExecutor(WithErrorHandler(ErrorHandlerFunc(func(r Runnable, e error) {}))), 0)
+
+		require.NoError(t, executor.ExecuteKeyedFunc("a", panicTask))
+		f, err := SubmitKeyedFunc(executor, "a", func(ctx context.Context) (string, error) {
+			return "after panic", nil
+		})
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "after panic", got)
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+
+	t.Run("panic and rejected", func(t *testing.T) {
+		pool := NewPoolExecutor(WithMaxConcurrent(1), WithMaxBlockingTasks(1),
+			WithRejectionHandler(DiscardRejectionPolicy{}),
+			WithErrorHandler(ErrorHandlerFunc(func(r Runnable, e error) {})))
+		executor := NewKeyedExecutor(pool, 0)
+
+		block := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, executor.ExecuteKeyedFunc("a", func(ctx context.Context) {
+			close(started)
+			<-block
+			panic("keyed panic")
+		}))
+		<-started
+		require.NoError(t, executor.ExecuteKeyedFunc("a", panicTask))
+		f, err := SubmitKeyedFunc(executor, "a", func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+		// fill the queue, so the key can not be resumed in a new worker after panic
+		queued := make(chan struct{})
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) { close(queued) }))
+		close(block)
+
+		_, err = f.Get(context.Background())
+		require.ErrorIs(t, err, ErrRejectedExecution)
+		<-queued
+		require.Equal(t, 0, executor.Keys())
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+
+	t.Run("evicted", func(t *testing.T) {
+		pool := NewPoolExecutor(WithMaxConcurrent(1), WithMaxBlockingTasks(1), WithRejectionHandler(DiscardOldestPolicy{}))
+		executor := NewKeyedExecutor(pool, 0)
+
+		block := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {
+			close(started)
+			<-block
+		}))
+		<-started
+		f, err := SubmitKeyedFunc(executor, "a", func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+		// evict the queued worker of the key
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {}))
+
+		_, err = f.Get(context.Background())
+		require.ErrorIs(t, err, ErrRejectedExecution)
+		require.Equal(t, 0, executor.Keys())
+		close(block)
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+
+	t.Run("shutdown now by the underlying executor", func(t *testing.T) {
+		pool := NewPoolExecutor(WithMaxConcurrent(1))
+		executor := NewKeyedExecutor(pool, 0)
+
+		block := make(chan struct{})
+		started := make(chan struct{})
+		require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {
+			close(started)
+			<-block
+		}))
+		<-started
+		require.NoError(t, executor.ExecuteKeyedFunc("a", func(ctx context.Context) {}))
+		require.Len(t, pool.ShutdownNow(), 1)
+		close(block)
+
+		require.ErrorIs(t, executor.ExecuteKeyedFunc("a", func(ctx context.Context) {}), ErrShutdown)
+		require.NoError(t, executor.Shutdown(context.Background()))
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		executor := NewKeyedExecutor(NewPoolExecutor(), 0)
+
+		block := make(chan struct{})
+		defer close(block)
+		for i := range 3 {
+			require.NoError(t, executor.ExecuteKeyedFunc(fmt.Sprint(i), func(ctx context.Context) { <-block }))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, executor.Shutdown(ctx), context.DeadlineExceeded)
+		require.Equal(t, 3, executor.Keys())
+	})
+}
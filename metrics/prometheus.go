@@ -0,0 +1,297 @@
+// Package metrics export the statistics of executors in Prometheus text format,
+// without any Prometheus client library.
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zhenzou/executors"
+	"github.com/zhenzou/executors/cron"
+)
+
+var (
+	ErrDuplicateName = errors.New("duplicate executor name")
+	ErrNoStats       = errors.New("executor not support stats")
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// StatsProvider the executor which can report statistics, like PoolExecutor and PoolScheduleExecutor.
+type StatsProvider interface {
+	Stats() executors.PoolStats
+}
+
+// CronTasksProvider the executor which can report the cron tasks, like PoolScheduleExecutor.
+type CronTasksProvider interface {
+	CronTasks() []cron.TaskInfo
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register register the executor to the default registry.
+func Register(name string, executor executors.Executor) error {
+	return defaultRegistry.Register(name, executor)
+}
+
+// Unregister remove the executor from the default registry.
+func Unregister(name string) {
+	defaultRegistry.Unregister(name)
+}
+
+// Handler return the http.Handler of the default registry.
+func Handler() http.Handler {
+	return defaultRegistry
+}
+
+// Registry the executors to export, keyed by the name label.
+type Registry struct {
+	locker    sync.RWMutex
+	executors map[string]StatsProvider
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		executors: map[string]StatsProvider{},
+	}
+}
+
+// Register register the executor with the name label,
+// will return ErrNoStats if the executor can not report statistics.
+func (r *Registry) Register(name string, executor executors.Executor) error {
+	provider, ok := executor.(StatsProvider)
+	if !ok {
+		return ErrNoStats
+	}
+
+	r.locker.Lock()
+	defer r.locker.Unlock()
+
+	if _, ok := r.executors[name]; ok {
+		return ErrDuplicateName
+	}
+	r.executors[name] = provider
+	return nil
+}
+
+func (r *Registry) Unregister(name string) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+
+	delete(r.executors, name)
+}
+
+// ServeHTTP implement http.Handler
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_ = r.Write(w)
+}
+
+type sample struct {
+	name   string
+	labels string
+	stats  executors.PoolStats
+	cron   []cron.TaskInfo
+}
+
+// Write write the metrics of all registered executors in Prometheus text format.
+func (r *Registry) Write(w io.Writer) error {
+	samples := r.collect()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range poolMetrics {
+		writeHeader(bw, m.name, m.help, m.typ)
+		for _, s := range samples {
+			fmt.Fprintf(bw, "%s{%s} %s\n", m.name, s.labels, formatFloat(m.value(s.stats)))
+		}
+	}
+
+	for _, h := range histogramMetrics {
+		writeHeader(bw, h.name, h.help, "histogram")
+		for _, s := range samples {
+			histogram, sum := h.value(s.stats)
+			writeHistogram(bw, h.name, s.labels, histogram, sum)
+		}
+	}
+
+	for _, m := range cronMetrics {
+		writeHeader(bw, m.name, m.help, "gauge")
+		for _, s := range samples {
+			for _, task := range s.cron {
+				fmt.Fprintf(bw, "%s{%s,task=\"%d\"} %s\n", m.name, s.labels, task.ID, formatFloat(m.value(task)))
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func (r *Registry) collect() []sample {
+	r.locker.RLock()
+	defer r.locker.RUnlock()
+
+	samples := make([]sample, 0, len(r.executors))
+	for name, provider := range r.executors {
+		s := sample{
+			name:   name,
+			labels: fmt.Sprintf("name=\"%s\"", escapeLabel(name)),
+			stats:  provider.Stats(),
+		}
+		if c, ok := provider.(CronTasksProvider); ok {
+			s.cron = c.CronTasks()
+		}
+		samples = append(samples, s)
+	}
+	slices.SortFunc(samples, func(a, b sample) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return samples
+}
+
+type poolMetric struct {
+	name  string
+	help  string
+	typ   string
+	value func(stats executors.PoolStats) float64
+}
+
+var poolMetrics = []poolMetric{
+	{
+		name: "executor_queued_tasks",
+		help: "The count of tasks waiting for a free worker.",
+		typ:  "gauge",
+		value: func(stats executors.PoolStats) float64 {
+			return float64(stats.Queued)
+		},
+	},
+	{
+		name: "executor_running_tasks",
+		help: "The count of running tasks.",
+		typ:  "gauge",
+		value: func(stats executors.PoolStats) float64 {
+			return float64(stats.Running)
+		},
+	},
+	{
+		name: "executor_peak_running_tasks",
+		help: "The max count of running tasks at the same time.",
+		typ:  "gauge",
+		value: func(stats executors.PoolStats) float64 {
+			return float64(stats.PeakRunning)
+		},
+	},
+	{
+		name: "executor_completed_tasks_total",
+		help: "The count of finished tasks, including failed and panicked.",
+		typ:  "counter",
+		value: func(stats executors.PoolStats) float64 {
+			return float64(stats.Completed)
+		},
+	},
+	{
+		name: "executor_failed_tasks_total",
+		help: "The count of futures completed with error.",
+		typ:  "counter",
+		value: func(stats executors.PoolStats) float64 {
+			return float64(stats.Failed)
+		},
+	},
+	{
+		name: "executor_panicked_tasks_total",
+		help: "The count of tasks panicked.",
+		typ:  "counter",
+		value: func(stats executors.PoolStats) float64 {
+			return float64(stats.Panicked)
+		},
+	},
+	{
+		name: "executor_rejected_tasks_total",
+		help: "The count of tasks rejected because no space to run or queue.",
+		typ:  "counter",
+		value: func(stats executors.PoolStats) float64 {
+			return float64(stats.Rejected)
+		},
+	},
+}
+
+type histogramMetric struct {
+	name  string
+	help  string
+	value func(stats executors.PoolStats) (executors.Histogram, float64)
+}
+
+var histogramMetrics = []histogramMetric{
+	{
+		name: "executor_task_queue_wait_seconds",
+		help: "The duration of tasks waiting for a free worker.",
+		value: func(stats executors.PoolStats) (executors.Histogram, float64) {
+			return stats.QueueWaitHistogram, stats.TotalQueueWait.Seconds()
+		},
+	},
+	{
+		name: "executor_task_run_seconds",
+		help: "The duration of tasks running.",
+		value: func(stats executors.PoolStats) (executors.Histogram, float64) {
+			return stats.RunTimeHistogram, stats.TotalRunTime.Seconds()
+		},
+	},
+}
+
+type cronMetric struct {
+	name  string
+	help  string
+	value func(task cron.TaskInfo) float64
+}
+
+var cronMetrics = []cronMetric{
+	{
+		name: "executor_cron_task_last_run_timestamp_seconds",
+		help: "The unix time of the last run of the cron task, 0 if never run.",
+		value: func(task cron.TaskInfo) float64 {
+			if task.LastRunTime.IsZero() {
+				return 0
+			}
+			return float64(task.LastRunTime.UnixMilli()) / 1e3
+		},
+	},
+	{
+		name: "executor_cron_task_next_run_timestamp_seconds",
+		help: "The unix time of the next run of the cron task.",
+		value: func(task cron.TaskInfo) float64 {
+			return float64(task.NextRunTime.UnixMilli()) / 1e3
+		},
+	},
+}
+
+func writeHeader(w io.Writer, name, help, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+}
+
+func writeHistogram(w io.Writer, name, labels string, h executors.Histogram, sum float64) {
+	var cumulative int64
+	for i, bound := range h.Bounds {
+		cumulative += h.Counts[i]
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(bound.Seconds()), cumulative)
+	}
+	cumulative += h.Counts[len(h.Bounds)]
+	fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, cumulative)
+	fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, formatFloat(sum))
+	fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, cumulative)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
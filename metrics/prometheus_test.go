@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhenzou/executors"
+)
+
+func TestRegistry(t *testing.T) {
+	pool := executors.NewPoolExecutor(executors.WithMaxConcurrent(2))
+	defer pool.Shutdown(context.Background())
+	scheduler := executors.NewPoolScheduleExecutor()
+	defer scheduler.Shutdown(context.Background())
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("pool", pool))
+	require.NoError(t, registry.Register(`sched"uler`, scheduler))
+	require.ErrorIs(t, registry.Register("pool", pool), ErrDuplicateName)
+
+	done := make(chan struct{})
+	require.NoError(t, pool.ExecuteFunc(func(ctx context.Context) {
+		close(done)
+	}))
+	<-done
+	_, err := scheduler.ScheduleFuncAtCronRate(func(ctx context.Context) {}, executors.CRONRule{Expr: "0 0 1 1 *"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return pool.(StatsProvider).Stats().Completed == 1
+	}, time.Second, time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, contentType, recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.String()
+	require.Contains(t, body, "# TYPE executor_queued_tasks gauge\n")
+	require.Contains(t, body, `executor_completed_tasks_total{name="pool"} 1`+"\n")
+	require.Contains(t, body, `executor_rejected_tasks_total{name="sched\"uler"} 0`+"\n")
+	require.Contains(t, body, "# TYPE executor_task_run_seconds histogram\n")
+	require.Contains(t, body, `executor_task_run_seconds_bucket{name="pool",le="+Inf"} 1`+"\n")
+	require.Contains(t, body, `executor_task_run_seconds_count{name="pool"} 1`+"\n")
+	require.Contains(t, body, `executor_cron_task_last_run_timestamp_seconds{name="sched\"uler",task=`)
+	require.Contains(t, body, `executor_cron_task_next_run_timestamp_seconds{name="sched\"uler",task=`)
+
+	registry.Unregister("pool")
+	recorder = httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.NotContains(t, recorder.Body.String(), `name="pool"`)
+}
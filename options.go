@@ -1,12 +1,43 @@
 package executors
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"time"
 )
 
+// RejectReason the reason why the task rejected.
+type RejectReason int
+
+const (
+	// RejectReasonFull no space to run or queue the task
+	RejectReasonFull RejectReason = iota + 1
+	// RejectReasonShutdown the executor shutdown already
+	RejectReasonShutdown
+)
+
+func (r RejectReason) String() string {
+	switch r {
+	case RejectReasonFull:
+		return "full"
+	case RejectReasonShutdown:
+		return "shutdown"
+	default:
+		return fmt.Sprintf("unknown reason %d", int(r))
+	}
+}
+
 type RejectionHandler interface {
-	RejectExecution(runnable Runnable, e Executor) error
+	// RejectExecution ctx the context of the submitter, context.Background() if not specified,
+	// pass it to the executor to resubmit the task with the original context and priority.
+	RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error
+}
+
+type RejectionHandlerFunc func(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error
+
+func (f RejectionHandlerFunc) RejectExecution(ctx context.Context, runnable Runnable, e Executor, reason RejectReason) error {
+	return f(ctx, runnable, e, reason)
 }
 
 type ErrorHandler interface {
@@ -19,19 +50,62 @@ func (f ErrorHandlerFunc) CatchError(runnable Runnable, e error) {
 	f(runnable, e)
 }
 
+// Interceptor intercept the execution of every task, including the scheduled and cron tasks,
+// like beforeExecute and afterExecute of ThreadPoolExecutor in Java.
+type Interceptor interface {
+	// BeforeExecute called before the task run, the returned context will be passed to the task.
+	// If panicked, the task will never run and be completed with the ErrPanic like a panicked task.
+	BeforeExecute(ctx context.Context, runnable Runnable) context.Context
+
+	// AfterExecute called after the task finished, err is ErrPanic if panicked,
+	// or the error of the Future completed with error.
+	AfterExecute(ctx context.Context, runnable Runnable, duration time.Duration, err error)
+}
+
 type _PoolExecutorOption func(opts *poolExecutorOptions)
 
 type poolExecutorOptions struct {
+	// CorePoolSize the count of workers to keep, 0 means create workers up to MaxConcurrent before queueing
+	CorePoolSize int
+	// MaxConcurrent the max count of workers
 	MaxConcurrent    int
 	MaxBlockingTasks int
 	ExecuteTimeout   time.Duration
 	ErrorHandler     ErrorHandler
 	RejectionHandler RejectionHandler
+	CallbackExecutor Executor
 	Logger           *slog.Logger
+
+	// KeepAlive the idle duration before the worker exit
+	KeepAlive time.Duration
+
+	// AllowCoreTimeout the core workers will exit after idle KeepAlive too
+	AllowCoreTimeout bool
+
+	// AntsPool run the tasks by ants.Pool instead of the native pool
+	AntsPool bool
+
+	// InheritCancel the task context will inherit the cancellation and deadline of the submitter context
+	InheritCancel bool
+
+	// PriorityQueue take the queued tasks by priority instead of FIFO
+	PriorityQueue bool
+
+	// PriorityAging the waiting duration to raise the priority of queued task by 1, never raise if 0
+	PriorityAging time.Duration
+
+	// Interceptors the BeforeExecute called in order, the AfterExecute called in reverse order
+	Interceptors []Interceptor
+
+	// ShutdownGracePeriod cancel the context of running tasks after the period since Shutdown called,
+	// will never cancel if 0.
+	ShutdownGracePeriod time.Duration
 }
 
 var _DefaultPoolExecutorOptions = poolExecutorOptions{
 	MaxConcurrent:    10,
+	KeepAlive:        1 * time.Second,
+	PriorityAging:    1 * time.Second,
 	ExecuteTimeout:   0,
 	ErrorHandler:     LogErrorHandler{},
 	RejectionHandler: NoopRejectionPolicy{},
@@ -44,6 +118,42 @@ func WithMaxConcurrent(concurrent int) _PoolExecutorOption {
 	}
 }
 
+// WithCorePoolSize set the count of workers to keep, like corePoolSize of ThreadPoolExecutor in Java.
+// Will create workers up to size before queueing the tasks,
+// and create workers above size up to MaxConcurrent only if the queue is full.
+// So the pool will never grow above size if MaxBlockingTasks is 0, which means the queue is unlimited.
+// Default 0, will create workers up to MaxConcurrent before queueing the tasks.
+// Only work for the native pool.
+func WithCorePoolSize(size int) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.CorePoolSize = size
+	}
+}
+
+// WithMaxPoolSize same as WithMaxConcurrent.
+func WithMaxPoolSize(size int) _PoolExecutorOption {
+	return WithMaxConcurrent(size)
+}
+
+// WithKeepAlive set the idle duration before the worker exit, default 1s.
+// The core workers will not exit unless WithAllowCoreTimeout.
+// Only work for the native pool.
+func WithKeepAlive(keepAlive time.Duration) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.KeepAlive = keepAlive
+	}
+}
+
+// WithAllowCoreTimeout make the core workers exit after idle KeepAlive too.
+// Only work for the native pool.
+func WithAllowCoreTimeout(allow bool) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.AllowCoreTimeout = allow
+	}
+}
+
+// WithMaxBlockingTasks set the max count of tasks waiting for a free worker, unlimited if 0.
+// The waiting tasks will be queued by the native pool, and blocked in Execute by ants pool.
 func WithMaxBlockingTasks(max int) _PoolExecutorOption {
 	return func(opts *poolExecutorOptions) {
 		opts.MaxBlockingTasks = max
@@ -73,3 +183,61 @@ func WithLogger(logger *slog.Logger) _PoolExecutorOption {
 		opts.Logger = logger
 	}
 }
+
+// WithCallbackExecutor set the default executor to run the async callbacks of the submitted futures,
+// like Future.ThenAsync and Future.CatchAsync.
+func WithCallbackExecutor(executor Executor) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.CallbackExecutor = executor
+	}
+}
+
+// WithShutdownGracePeriod cancel the context of the running tasks after period since Shutdown called,
+// so the long-running tasks can stop cooperatively.
+// Default 0, will wait the running tasks finished without cancel.
+func WithShutdownGracePeriod(period time.Duration) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.ShutdownGracePeriod = period
+	}
+}
+
+// WithInheritCancel make the task context inherit the cancellation and deadline of the context
+// passed to ExecuteContext and SubmitContext, only the values will be inherited by default.
+func WithInheritCancel(inherit bool) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.InheritCancel = inherit
+	}
+}
+
+// WithAntsPool run the tasks by ants.Pool instead of the native pool.
+// The queued tasks can not be inspected or reordered with ants pool.
+func WithAntsPool() _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.AntsPool = true
+	}
+}
+
+// WithInterceptors add interceptors to intercept the execution of every task.
+// The BeforeExecute will be called in order, and the AfterExecute will be called in reverse order.
+func WithInterceptors(interceptors ...Interceptor) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.Interceptors = append(opts.Interceptors, interceptors...)
+	}
+}
+
+// WithPriorityQueue take the queued tasks by priority instead of FIFO, FIFO within the same priority.
+// The priority can be specified by ExecuteWithPriority, SubmitWithPriority or Prioritized.
+// Only work for the native pool.
+func WithPriorityQueue() _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.PriorityQueue = true
+	}
+}
+
+// WithPriorityAging raise the priority of queued task by 1 every aging duration,
+// to prevent starvation of the low priority tasks, default 1s, never raise if 0.
+func WithPriorityAging(aging time.Duration) _PoolExecutorOption {
+	return func(opts *poolExecutorOptions) {
+		opts.PriorityAging = aging
+	}
+}
@@ -1,10 +1,16 @@
 package executors
 
 import (
+	"cmp"
 	"context"
 	"errors"
-
-	"github.com/panjf2000/ants/v2"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 func NewPoolExecutor(opts ..._PoolExecutorOption) Executor {
@@ -20,36 +26,141 @@ func internalNewPoolExecutorService[T any](opts ..._PoolExecutorOption) *PoolExe
 	for _, o := range opts {
 		o(&opt)
 	}
-	pool, err := ants.NewPool(opt.MaxConcurrent,
-		ants.WithMaxBlockingTasks(opt.MaxBlockingTasks),
-		// do nothing, will handle by ErrorHandler
-		ants.WithPanicHandler(func(cause interface{}) {}))
-	if err != nil {
-		panic(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	executor := &PoolExecutor[T]{
+		opts:       opt,
+		ctx:        ctx,
+		cancel:     cancel,
+		pending:    map[*poolTask]struct{}{},
+		terminated: make(chan struct{}),
 	}
-	return &PoolExecutor[T]{
-		opts: opt,
-		pool: pool,
+	if opt.AntsPool {
+		executor.pool = newAntsPool(opt.MaxConcurrent, opt.MaxBlockingTasks, executor.runTask)
+	} else {
+		pool := newNativePool(max(opt.MaxConcurrent, opt.CorePoolSize), opt.MaxBlockingTasks, executor.runTask)
+		pool.coreWorkers = opt.CorePoolSize
+		pool.keepAlive = opt.KeepAlive
+		pool.allowCoreTimeout = opt.AllowCoreTimeout
+		if opt.PriorityQueue {
+			pool.queue = newPriorityTaskQueue(opt.PriorityAging)
+		}
+		executor.pool = pool
 	}
+	return executor
 }
 
 type PoolExecutor[T any] struct {
 	opts poolExecutorOptions
-	pool *ants.Pool
+	pool workerPool
+
+	// ctx the root context of all tasks,
+	// will be canceled by ShutdownNow, or after the grace period since Shutdown called
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	locker sync.Mutex
+	// pending the tasks submitted but not started
+	pending map[*poolTask]struct{}
+	// submitted the count of tasks submitted, used as the id of the next task
+	submitted uint64
+	// tasks the count of pending and running tasks
+	tasks      int
+	shutdown   atomic.Bool
+	terminated chan struct{}
+	// space closed when a queued task started or a running task done, nil if no waiter
+	space chan struct{}
+
+	stats poolStats
+}
+
+// poolTask the task submitted to pool.
+type poolTask struct {
+	// id the submission order of the task in executor
+	id       uint64
+	runnable Runnable
+	// ctx the context of the submitter, nil if not specified
+	ctx context.Context
+	// submitted the time of the task submitted
+	submitted time.Time
+	// priority the higher priority task will be taken first by the priority queue
+	priority int
+	// seq the sequence of the task in queue, keep FIFO within the same priority
+	seq uint64
 }
 
 func (p *PoolExecutor[T]) Execute(r Runnable) error {
-	err := p.pool.Submit(func() {
-		ctx, cancelFunc := p.newContext()
-		defer cancelFunc()
-		defer func() {
-			if cause := recover(); cause != nil {
-				p.opts.Logger.Debug("failed to execute task")
-				p.opts.ErrorHandler.CatchError(r, ErrPanic{Cause: cause})
-			}
-		}()
-		r.Run(ctx)
-	})
+	return p.execute(nil, r, priorityOf(r))
+}
+
+func (p *PoolExecutor[T]) ExecuteContext(ctx context.Context, r Runnable) error {
+	return p.execute(ctx, r, priorityOf(r))
+}
+
+// ExecuteWithPriority execute the runnable with priority, only work with WithPriorityQueue.
+func (p *PoolExecutor[T]) ExecuteWithPriority(r Runnable, priority int) error {
+	return p.execute(nil, r, priority)
+}
+
+func (p *PoolExecutor[T]) execute(ctx context.Context, r Runnable, priority int) error {
+	err := p.tryExecute(ctx, r, priority)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrShutdown):
+		return p.opts.RejectionHandler.RejectExecution(rejectionContext(ctx, priority), r, p, RejectReasonShutdown)
+	case errors.Is(err, errPoolOverload):
+		p.stats.rejected.Add(1)
+		return p.opts.RejectionHandler.RejectExecution(rejectionContext(ctx, priority), r, p, RejectReasonFull)
+	default:
+		return err
+	}
+}
+
+// rejectedTaskKey the context key of the original submission of the rejected task.
+type rejectedTaskKey struct{}
+
+// rejectedTask the original submission of the rejected task,
+// so the task can be resubmitted by the RejectionHandler with the same context and priority.
+type rejectedTask struct {
+	// ctx the context of the submitter, nil if not specified
+	ctx      context.Context
+	priority int
+}
+
+// rejectionContext return the context passed to the RejectionHandler,
+// the context of the submitter with the original submission.
+func rejectionContext(ctx context.Context, priority int) context.Context {
+	return context.WithValue(submitterContext(ctx), rejectedTaskKey{}, rejectedTask{ctx: ctx, priority: priority})
+}
+
+// submitterContext return ctx, or context.Background() if the context of submitter not specified.
+func submitterContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// submissionOf return the original context and priority if ctx passed to the RejectionHandler,
+// or ctx and the priority of r otherwise.
+func submissionOf(ctx context.Context, r Runnable) (context.Context, int) {
+	if ctx != nil {
+		if task, ok := ctx.Value(rejectedTaskKey{}).(rejectedTask); ok {
+			return task.ctx, task.priority
+		}
+	}
+	return ctx, priorityOf(r)
+}
+
+// tryExecute submit the task to pool without the RejectionHandler,
+// will return ErrShutdown if shutdown, errPoolOverload if no space to run or queue the task.
+func (p *PoolExecutor[T]) tryExecute(ctx context.Context, r Runnable, priority int) error {
+	task, err := p.addTask(ctx, r, priority)
+	if err != nil {
+		return err
+	}
+
+	err = p.pool.Submit(task)
 
 	if err == nil {
 		p.opts.Logger.Debug("submitted a new task")
@@ -58,14 +169,188 @@ func (p *PoolExecutor[T]) Execute(r Runnable) error {
 
 	p.opts.Logger.Debug("failed to submit task")
 
-	switch {
-	case errors.Is(err, ants.ErrPoolClosed):
+	p.removeTask(task)
+
+	if errors.Is(err, errPoolClosed) {
 		return ErrShutdown
-	case errors.Is(err, ants.ErrPoolOverload):
-		return p.opts.RejectionHandler.RejectExecution(r, p)
-	default:
+	}
+	return err
+}
+
+// RunInline run the runnable in the caller goroutine with the same semantics as the pooled execution,
+// including ExecuteTimeout, panic recovery, ErrorHandler, interceptors and stats.
+// The original context and priority will be used if ctx passed to the RejectionHandler.
+// Will return ErrShutdown if shutdown already.
+func (p *PoolExecutor[T]) RunInline(ctx context.Context, r Runnable) error {
+	ctx, priority := submissionOf(ctx, r)
+	task, err := p.addTask(ctx, r, priority)
+	if err != nil {
 		return err
 	}
+	p.runTask(task)
+	return nil
+}
+
+// offer execute the runnable without the RejectionHandler, with the original context and priority
+// if ctx passed to the RejectionHandler. Will wait for space until ctx done if wait,
+// or return ErrRejectedExecution immediately if no space.
+func (p *PoolExecutor[T]) offer(ctx context.Context, r Runnable, wait bool) error {
+	taskCtx, priority := submissionOf(ctx, r)
+	for {
+		space := p.waitSpace()
+		err := p.tryExecute(taskCtx, r, priority)
+		if !errors.Is(err, errPoolOverload) {
+			return err
+		}
+		if !wait {
+			return ErrRejectedExecution
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrRejectedExecution, context.Cause(ctx))
+		case <-space:
+		}
+	}
+}
+
+// evictOldest remove the queued task submitted earliest, regardless of priority.
+func (p *PoolExecutor[T]) evictOldest() (Runnable, bool) {
+	task, ok := p.pool.Evict()
+	if !ok {
+		return nil, false
+	}
+	p.removeTask(task)
+	return task.runnable, true
+}
+
+// waitSpace return a chan which will be closed when a queued task started or a running task done.
+func (p *PoolExecutor[T]) waitSpace() <-chan struct{} {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if p.space == nil {
+		p.space = make(chan struct{})
+	}
+	return p.space
+}
+
+// notifySpace wakeup all the waiters of space, should be called with lock.
+func (p *PoolExecutor[T]) notifySpace() {
+	if p.space != nil {
+		close(p.space)
+		p.space = nil
+	}
+}
+
+func (p *PoolExecutor[T]) runTask(task *poolTask) {
+	if !p.startTask(task) {
+		// dropped by ShutdownNow
+		return
+	}
+	defer p.doneTask()
+
+	r := task.runnable
+	started := time.Now()
+	p.stats.start(started.Sub(task.submitted))
+	panicked := false
+	defer func() {
+		p.stats.done(time.Since(started), r, panicked)
+	}()
+
+	ctx, cancelFunc := p.newContext(task.ctx)
+	defer cancelFunc()
+	intercepted := 0
+	defer func() {
+		var err error
+		if cause := recover(); cause != nil {
+			panicked = true
+			p.opts.Logger.Debug("failed to execute task", slog.Any("cause", cause))
+
+			// the panic of FutureTask is ErrPanic already
+			errPanic, ok := cause.(ErrPanic)
+			if !ok {
+				errPanic = newErrPanic(cause, r)
+			}
+			// the task never ran if BeforeExecute panicked, complete it to release the waiters
+			if c, ok := r.(completable); ok {
+				c.completeError(errPanic)
+			}
+			p.opts.ErrorHandler.CatchError(r, errPanic)
+			err = errPanic
+		} else {
+			err = failureOf(r)
+		}
+		// the AfterExecute called in reverse order, only for the interceptors whose BeforeExecute returned
+		duration := time.Since(started)
+		for i := intercepted - 1; i >= 0; i-- {
+			p.opts.Interceptors[i].AfterExecute(ctx, r, duration, err)
+		}
+	}()
+	for _, interceptor := range p.opts.Interceptors {
+		ctx = interceptor.BeforeExecute(ctx, r)
+		intercepted++
+	}
+	r.Run(ctx)
+}
+
+// addTask add a pending task, will return ErrShutdown if shutdown already.
+func (p *PoolExecutor[T]) addTask(ctx context.Context, r Runnable, priority int) (*poolTask, error) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if p.shutdown.Load() {
+		return nil, ErrShutdown
+	}
+	p.submitted++
+	task := &poolTask{id: p.submitted, runnable: r, ctx: ctx, submitted: time.Now(), priority: priority}
+	p.pending[task] = struct{}{}
+	p.tasks++
+	return task, nil
+}
+
+// removeTask remove the pending task failed to submit.
+func (p *PoolExecutor[T]) removeTask(task *poolTask) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if _, ok := p.pending[task]; !ok {
+		return
+	}
+	delete(p.pending, task)
+	p.tasks--
+	p.tryTerminate()
+}
+
+// startTask mark the pending task running, will return false if the task dropped.
+func (p *PoolExecutor[T]) startTask(task *poolTask) bool {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if _, ok := p.pending[task]; !ok {
+		return false
+	}
+	delete(p.pending, task)
+	p.notifySpace()
+	return true
+}
+
+func (p *PoolExecutor[T]) doneTask() {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.tasks--
+	p.notifySpace()
+	p.tryTerminate()
+}
+
+// tryTerminate terminate the executor if shutdown and no task left, should be called with lock.
+func (p *PoolExecutor[T]) tryTerminate() {
+	if !p.shutdown.Load() || p.tasks > 0 || p.IsTerminated() {
+		return
+	}
+	close(p.terminated)
+	p.cancel()
+	go p.pool.Release()
 }
 
 func (p *PoolExecutor[T]) ExecuteFunc(fn func(ctx context.Context)) error {
@@ -73,8 +358,22 @@ func (p *PoolExecutor[T]) ExecuteFunc(fn func(ctx context.Context)) error {
 }
 
 func (p *PoolExecutor[T]) Submit(callable Callable[T]) (Future[T], error) {
+	return p.submit(nil, callable, priorityOf(callable))
+}
+
+func (p *PoolExecutor[T]) SubmitContext(ctx context.Context, callable Callable[T]) (Future[T], error) {
+	return p.submit(ctx, callable, priorityOf(callable))
+}
+
+// SubmitWithPriority submit the callable with priority, only work with WithPriorityQueue.
+func (p *PoolExecutor[T]) SubmitWithPriority(callable Callable[T], priority int) (Future[T], error) {
+	return p.submit(nil, callable, priority)
+}
+
+func (p *PoolExecutor[T]) submit(ctx context.Context, callable Callable[T], priority int) (Future[T], error) {
 	f := NewFutureTask[T](callable)
-	err := p.Execute(f)
+	f.callbackExecutor = p.opts.CallbackExecutor
+	err := p.execute(ctx, f, priority)
 	if err != nil {
 		return nil, err
 	}
@@ -85,24 +384,149 @@ func (p *PoolExecutor[T]) SubmitFunc(fn func(ctx context.Context) (T, error)) (F
 	return p.Submit(CallableFunc[T](fn))
 }
 
+func (p *PoolExecutor[T]) InvokeAll(ctx context.Context, callables []Callable[T]) ([]Future[T], error) {
+	futures, err := p.submitAll(callables)
+	if err != nil {
+		return nil, err
+	}
+	_, err = AllSettled(ctx, futures...).Get(context.Background())
+	return futures, err
+}
+
+func (p *PoolExecutor[T]) InvokeAny(ctx context.Context, callables []Callable[T]) (T, error) {
+	futures, err := p.submitAll(callables)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return AnyOf(ctx, futures...).Get(context.Background())
+}
+
+func (p *PoolExecutor[T]) submitAll(callables []Callable[T]) ([]Future[T], error) {
+	futures := make([]Future[T], 0, len(callables))
+	for _, callable := range callables {
+		f, err := p.Submit(callable)
+		if err != nil {
+			for _, submitted := range futures {
+				submitted.Cancel()
+			}
+			return nil, err
+		}
+		futures = append(futures, f)
+	}
+	return futures, nil
+}
+
 func (p *PoolExecutor[T]) Shutdown(ctx context.Context) error {
-	ch := make(chan struct{})
-	go func() {
-		p.pool.Release()
-		ch <- struct{}{}
-	}()
+	p.locker.Lock()
+	if !p.shutdown.Swap(true) && p.opts.ShutdownGracePeriod > 0 {
+		time.AfterFunc(p.opts.ShutdownGracePeriod, p.cancel)
+	}
+	p.notifySpace()
+	p.tryTerminate()
+	p.locker.Unlock()
+
+	return p.AwaitTermination(ctx)
+}
 
+func (p *PoolExecutor[T]) ShutdownNow() []Runnable {
+	p.locker.Lock()
+	p.shutdown.Store(true)
+	tasks := slices.SortedFunc(maps.Keys(p.pending), func(a, b *poolTask) int {
+		return cmp.Compare(a.id, b.id)
+	})
+	runnables := make([]Runnable, 0, len(tasks))
+	for _, task := range tasks {
+		runnables = append(runnables, task.runnable)
+	}
+	p.tasks -= len(p.pending)
+	clear(p.pending)
+	p.notifySpace()
+	p.tryTerminate()
+	p.locker.Unlock()
+
+	p.cancel()
+	return runnables
+}
+
+func (p *PoolExecutor[T]) AwaitTermination(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
-	case <-ch:
+	case <-p.terminated:
 		return nil
 	}
 }
 
-func (p *PoolExecutor[T]) newContext() (context.Context, context.CancelFunc) {
+func (p *PoolExecutor[T]) IsShutdown() bool {
+	return p.shutdown.Load()
+}
+
+func (p *PoolExecutor[T]) IsTerminated() bool {
+	select {
+	case <-p.terminated:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stats return the statistics of the executor since created.
+func (p *PoolExecutor[T]) Stats() PoolStats {
+	stats := p.stats.snapshot()
+	stats.Queued = p.pool.Waiting()
+	return stats
+}
+
+// MaxConcurrent return the current max count of concurrent tasks.
+func (p *PoolExecutor[T]) MaxConcurrent() int {
+	return p.pool.MaxWorkers()
+}
+
+// SetMaxConcurrent change the max count of concurrent tasks at runtime, ignore if n <= 0.
+// The extra workers will exit after the running tasks finished if decreased.
+func (p *PoolExecutor[T]) SetMaxConcurrent(n int) {
+	p.pool.SetMaxWorkers(n)
+}
+
+// MaxBlockingTasks return the current max count of tasks waiting for a free worker, unlimited if 0.
+func (p *PoolExecutor[T]) MaxBlockingTasks() int {
+	return p.pool.MaxQueued()
+}
+
+// SetMaxBlockingTasks change the max count of tasks waiting for a free worker at runtime, unlimited if 0.
+// The waiting tasks will not be rejected if decreased.
+// Will return errors.ErrUnsupported with ants pool, which can not change it at runtime.
+func (p *PoolExecutor[T]) SetMaxBlockingTasks(n int) error {
+	return p.pool.SetMaxQueued(n)
+}
+
+// newContext create the context of task from the root context,
+// will inherit the values of parent if not nil,
+// and the cancellation of parent if InheritCancel enabled.
+func (p *PoolExecutor[T]) newContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancelParent := p.ctx, context.CancelFunc(func() {})
+	if parent != nil {
+		if !p.opts.InheritCancel {
+			parent = context.WithoutCancel(parent)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(parent)
+		stop := context.AfterFunc(p.ctx, cancel)
+		cancelParent = func() {
+			stop()
+			cancel()
+		}
+	}
+
+	var cancelFunc context.CancelFunc
 	if p.opts.ExecuteTimeout == 0 {
-		return context.WithCancel(context.Background())
+		ctx, cancelFunc = context.WithCancel(ctx)
+	} else {
+		ctx, cancelFunc = context.WithTimeout(ctx, p.opts.ExecuteTimeout)
+	}
+	return ctx, func() {
+		cancelFunc()
+		cancelParent()
 	}
-	return context.WithTimeout(context.Background(), p.opts.ExecuteTimeout)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -50,6 +51,58 @@ func TestPoolExecutor_Execute(t *testing.T) {
 	require.True(t, errCaught)
 }
 
+func panicTask(ctx context.Context) {
+	panic("panic task")
+}
+
+func TestPoolExecutor_ExecutePanic(t *testing.T) {
+	targetErr := errors.New("test")
+	ch := make(chan error, 1)
+	service := NewPoolExecutor(
+		WithMaxConcurrent(10),
+		WithErrorHandler(ErrorHandlerFunc(func(runnable Runnable, e error) {
+			ch <- e
+		})))
+
+	err := service.Execute(RunnableFunc(panicTask))
+	require.NoError(t, err)
+
+	caught := <-ch
+	var errPanic ErrPanic
+	require.ErrorAs(t, caught, &errPanic)
+	require.Equal(t, "panic task", errPanic.Cause)
+	require.NotNil(t, errPanic.Task)
+	require.Contains(t, string(errPanic.Stack), "panicTask")
+
+	err = service.Execute(RunnableFunc(func(ctx context.Context) {
+		panic(targetErr)
+	}))
+	require.NoError(t, err)
+	require.ErrorIs(t, <-ch, targetErr)
+}
+
+func TestPoolExecutor_NoopErrorHandler(t *testing.T) {
+	// NoopErrorHandler panics again, the worker should survive and keep running the tasks
+	service := NewPoolExecutorService[int](WithMaxConcurrent(1), WithErrorHandler(NoopErrorHandler{}))
+	defer service.Shutdown(context.Background())
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, service.ExecuteFunc(panicTask))
+	}
+	f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	got, err := f.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, got)
+
+	pool := service.(*PoolExecutor[int]).pool.(*nativePool)
+	pool.locker.Lock()
+	defer pool.locker.Unlock()
+	require.Equal(t, 1, pool.workers)
+}
+
 type Person struct {
 	Name string
 }
@@ -132,3 +185,354 @@ func TestPoolExecutor_Submit(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestPoolExecutor_InvokeAll(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	callable := func(val int, delay time.Duration) Callable[int] {
+		return CallableFunc[int](func(ctx context.Context) (int, error) {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(delay):
+				return val, nil
+			}
+		})
+	}
+
+	t.Run("all completed", func(t *testing.T) {
+		futures, err := service.InvokeAll(context.Background(), []Callable[int]{
+			callable(1, 20*time.Millisecond),
+			callable(2, 10*time.Millisecond),
+		})
+		require.NoError(t, err)
+		require.Len(t, futures, 2)
+		for i, f := range futures {
+			require.True(t, f.Completed())
+			got, err := f.Get(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, i+1, got)
+		}
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		futures, err := service.InvokeAll(ctx, []Callable[int]{
+			callable(1, 10*time.Millisecond),
+			callable(2, time.Second),
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Len(t, futures, 2)
+		require.Eventually(t, futures[1].Canceled, time.Second, time.Millisecond)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		service := NewPoolExecutorService[int](WithMaxConcurrent(1), WithMaxBlockingTasks(1))
+		block := make(chan struct{})
+		defer close(block)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, _ = service.SubmitFunc(func(ctx context.Context) (int, error) {
+					<-block
+					return 0, nil
+				})
+			}()
+		}
+		require.Eventually(t, func() bool {
+			_, err := service.InvokeAll(context.Background(), []Callable[int]{callable(1, 0)})
+			return errors.Is(err, ErrRejectedExecution)
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestPoolExecutor_InvokeAny(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+
+	got, err := service.InvokeAny(context.Background(), []Callable[int]{
+		CallableFunc[int](func(ctx context.Context) (int, error) {
+			return 0, errors.New("failed")
+		}),
+		CallableFunc[int](func(ctx context.Context) (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return 2, nil
+		}),
+		CallableFunc[int](func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 3, ctx.Err()
+		}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, got)
+
+	_, err = service.InvokeAny(context.Background(), nil)
+	require.ErrorIs(t, err, ErrEmptyFutures)
+}
+
+func pendingTasks[T any](p *PoolExecutor[T]) int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	return len(p.pending)
+}
+
+func TestPoolExecutor_Shutdown(t *testing.T) {
+	executor := internalNewPoolExecutorService[any](WithMaxConcurrent(1))
+
+	var counter atomic.Int32
+	for i := 0; i < 3; i++ {
+		go func() {
+			_ = executor.ExecuteFunc(func(ctx context.Context) {
+				time.Sleep(20 * time.Millisecond)
+				counter.Add(1)
+			})
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return pendingTasks(executor) == 2
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, executor.Shutdown(context.Background()))
+	require.True(t, executor.IsShutdown())
+	require.True(t, executor.IsTerminated())
+	require.Equal(t, int32(3), counter.Load())
+
+	err := executor.ExecuteFunc(func(ctx context.Context) {})
+	require.ErrorIs(t, err, ErrShutdown)
+}
+
+func TestPoolExecutor_ShutdownNow(t *testing.T) {
+	executor := internalNewPoolExecutorService[any](WithMaxConcurrent(1))
+
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	err := executor.ExecuteFunc(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+	})
+	require.NoError(t, err)
+	<-started
+
+	var executed atomic.Bool
+	for i := 0; i < 2; i++ {
+		go func() {
+			_ = executor.ExecuteFunc(func(ctx context.Context) {
+				executed.Store(true)
+			})
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return pendingTasks(executor) == 2
+	}, time.Second, time.Millisecond)
+
+	runnables := executor.ShutdownNow()
+	require.Len(t, runnables, 2)
+	require.True(t, executor.IsShutdown())
+	<-canceled
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, executor.AwaitTermination(ctx))
+	require.True(t, executor.IsTerminated())
+	require.False(t, executed.Load())
+}
+
+type indexRunnable int
+
+func (r indexRunnable) Run(ctx context.Context) {}
+
+func TestPoolExecutor_ShutdownNowOrder(t *testing.T) {
+	executor := NewPoolExecutor(WithMaxConcurrent(1))
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, executor.ExecuteFunc(func(ctx context.Context) {
+		close(started)
+		<-block
+	}))
+	<-started
+	defer close(block)
+
+	var want []Runnable
+	for i := range 20 {
+		want = append(want, indexRunnable(i))
+		require.NoError(t, executor.Execute(indexRunnable(i)))
+	}
+	require.Equal(t, want, executor.ShutdownNow())
+}
+
+func TestPoolExecutor_ShutdownGracePeriod(t *testing.T) {
+	executor := NewPoolExecutor(WithMaxConcurrent(1), WithShutdownGracePeriod(50*time.Millisecond))
+
+	started := make(chan struct{})
+	err := executor.ExecuteFunc(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+	})
+	require.NoError(t, err)
+	<-started
+
+	start := time.Now()
+	require.NoError(t, executor.Shutdown(context.Background()))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	require.True(t, executor.IsTerminated())
+}
+
+type traceKey struct{}
+
+func TestPoolExecutor_ExecuteContext(t *testing.T) {
+	t.Run("inherit values", func(t *testing.T) {
+		service := NewPoolExecutorService[string](WithMaxConcurrent(10))
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace"))
+		cancel()
+
+		f, err := service.SubmitContext(ctx, CallableFunc[string](func(ctx context.Context) (string, error) {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return ctx.Value(traceKey{}).(string), nil
+		}))
+		require.NoError(t, err)
+
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "trace", got)
+	})
+
+	t.Run("inherit cancel", func(t *testing.T) {
+		executor := NewPoolExecutor(WithMaxConcurrent(10), WithInheritCancel(true))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled := make(chan struct{})
+		err := executor.ExecuteContext(ctx, RunnableFunc(func(ctx context.Context) {
+			<-ctx.Done()
+			close(canceled)
+		}))
+		require.NoError(t, err)
+
+		cancel()
+		<-canceled
+	})
+
+	t.Run("canceled by shutdown", func(t *testing.T) {
+		executor := NewPoolExecutor(WithMaxConcurrent(10))
+
+		started := make(chan struct{})
+		canceled := make(chan struct{})
+		err := executor.ExecuteContext(context.Background(), RunnableFunc(func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			close(canceled)
+		}))
+		require.NoError(t, err)
+		<-started
+
+		executor.ShutdownNow()
+		<-canceled
+	})
+}
+
+type recordInterceptor struct {
+	name    string
+	locker  sync.Mutex
+	records []string
+	errs    chan error
+}
+
+func (i *recordInterceptor) record(s string) {
+	i.locker.Lock()
+	defer i.locker.Unlock()
+	i.records = append(i.records, s)
+}
+
+func (i *recordInterceptor) BeforeExecute(ctx context.Context, r Runnable) context.Context {
+	i.record("before " + i.name)
+	return context.WithValue(ctx, traceKey{}, i.name)
+}
+
+func (i *recordInterceptor) AfterExecute(ctx context.Context, r Runnable, duration time.Duration, err error) {
+	i.record("after " + i.name)
+	if i.errs != nil {
+		i.errs <- err
+	}
+}
+
+type panicInterceptor struct{}
+
+func (panicInterceptor) BeforeExecute(ctx context.Context, r Runnable) context.Context {
+	panic("before execute")
+}
+
+func (panicInterceptor) AfterExecute(ctx context.Context, r Runnable, duration time.Duration, err error) {
+	panic("after execute")
+}
+
+func TestPoolExecutor_Interceptors(t *testing.T) {
+	// the AfterExecute of first called last
+	first := &recordInterceptor{name: "first", errs: make(chan error, 1)}
+	second := &recordInterceptor{name: "second"}
+	service := NewPoolExecutorService[string](WithInterceptors(first, second),
+		WithErrorHandler(ErrorHandlerFunc(func(r Runnable, e error) {})))
+
+	t.Run("order and context", func(t *testing.T) {
+		f, err := service.SubmitFunc(func(ctx context.Context) (string, error) {
+			return ctx.Value(traceKey{}).(string), nil
+		})
+		require.NoError(t, err)
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "second", got)
+		require.NoError(t, <-first.errs)
+		require.Equal(t, []string{"before first", "after first"}, first.records)
+		require.Equal(t, []string{"before second", "after second"}, second.records)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		targetErr := errors.New("failed")
+		_, err := service.SubmitFunc(func(ctx context.Context) (string, error) {
+			return "", targetErr
+		})
+		require.NoError(t, err)
+		require.ErrorIs(t, <-first.errs, targetErr)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		require.NoError(t, service.ExecuteFunc(panicTask))
+		var errPanic ErrPanic
+		require.ErrorAs(t, <-first.errs, &errPanic)
+	})
+
+	t.Run("before execute panic", func(t *testing.T) {
+		caught := make(chan error, 1)
+		last := &recordInterceptor{name: "last"}
+		executor := NewPoolExecutorService[string](WithInterceptors(first, panicInterceptor{}, last),
+			WithErrorHandler(ErrorHandlerFunc(func(r Runnable, e error) {
+				caught <- e
+			})))
+		defer executor.Shutdown(context.Background())
+
+		f, err := executor.SubmitFunc(func(ctx context.Context) (string, error) {
+			return "never", nil
+		})
+		require.NoError(t, err)
+		_, err = f.Get(context.Background())
+		var errPanic ErrPanic
+		require.ErrorAs(t, err, &errPanic)
+		require.Equal(t, "before execute", errPanic.Cause)
+		require.ErrorAs(t, <-caught, &errPanic)
+		require.ErrorAs(t, <-first.errs, &errPanic)
+		require.Empty(t, last.records)
+		require.Equal(t, int64(1), executor.(*PoolExecutor[string]).Stats().Panicked)
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		interceptor := &recordInterceptor{name: "scheduled", errs: make(chan error, 1)}
+		executor := NewPoolScheduleExecutor(WithInterceptors(interceptor))
+		defer executor.Shutdown(context.Background())
+
+		_, err := executor.ScheduleFunc(func(ctx context.Context) {}, 10*time.Millisecond)
+		require.NoError(t, err)
+		require.NoError(t, <-interceptor.errs)
+	})
+}
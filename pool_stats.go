@@ -0,0 +1,181 @@
+package executors
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// PoolStats the statistics of PoolExecutor since created.
+type PoolStats struct {
+	// Running the count of running tasks
+	Running int
+	// Queued the count of tasks waiting for a free worker
+	Queued int
+
+	// Completed the count of finished tasks, including failed and panicked
+	Completed int64
+	// Failed the count of futures completed with error, not including panicked
+	Failed int64
+	// Panicked the count of tasks panicked
+	Panicked int64
+	// Rejected the count of tasks rejected because no space to run or queue
+	Rejected int64
+
+	// PeakRunning the max count of running tasks at the same time
+	PeakRunning int64
+
+	// TotalQueueWait the total duration of tasks waiting for a free worker
+	TotalQueueWait time.Duration
+	// MaxQueueWait the max duration of a task waiting for a free worker
+	MaxQueueWait time.Duration
+	// TotalRunTime the total duration of tasks running
+	TotalRunTime time.Duration
+	// MaxRunTime the max duration of a task running
+	MaxRunTime time.Duration
+
+	// QueueWaitHistogram the histogram of the durations of tasks waiting for a free worker
+	QueueWaitHistogram Histogram
+	// RunTimeHistogram the histogram of the durations of tasks running
+	RunTimeHistogram Histogram
+}
+
+// Histogram the distribution of durations.
+type Histogram struct {
+	// Bounds the upper bounds of the buckets, from 5ms to 10s
+	Bounds []time.Duration
+	// Counts the count of durations in each bucket, not cumulative,
+	// has one more bucket than Bounds for the durations greater than the last bound.
+	Counts []int64
+}
+
+// AvgQueueWait return the average duration of completed tasks waiting for a free worker.
+func (s PoolStats) AvgQueueWait() time.Duration {
+	if s.Completed == 0 {
+		return 0
+	}
+	return s.TotalQueueWait / time.Duration(s.Completed)
+}
+
+// AvgRunTime return the average duration of completed tasks running.
+func (s PoolStats) AvgRunTime() time.Duration {
+	if s.Completed == 0 {
+		return 0
+	}
+	return s.TotalRunTime / time.Duration(s.Completed)
+}
+
+// poolStats the lock-free counters of PoolExecutor.
+type poolStats struct {
+	running        atomic.Int64
+	completed      atomic.Int64
+	failed         atomic.Int64
+	panicked       atomic.Int64
+	rejected       atomic.Int64
+	peakRunning    atomic.Int64
+	totalQueueWait atomic.Int64
+	maxQueueWait   atomic.Int64
+	totalRunTime   atomic.Int64
+	maxRunTime     atomic.Int64
+	queueWait      histogram
+	runTime        histogram
+}
+
+// histogram the lock-free histogram with durationBuckets.
+type histogram struct {
+	counts [len(durationBuckets) + 1]atomic.Int64
+}
+
+// durationBuckets the upper bounds of the buckets of duration histograms.
+var durationBuckets = [...]time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+func (h *histogram) observe(d time.Duration) {
+	i, _ := slices.BinarySearch(durationBuckets[:], d)
+	h.counts[i].Add(1)
+}
+
+func (h *histogram) snapshot() Histogram {
+	counts := make([]int64, len(h.counts))
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+	}
+	return Histogram{
+		Bounds: slices.Clone(durationBuckets[:]),
+		Counts: counts,
+	}
+}
+
+func (s *poolStats) start(queueWait time.Duration) {
+	storeMax(&s.peakRunning, s.running.Add(1))
+	s.totalQueueWait.Add(int64(queueWait))
+	storeMax(&s.maxQueueWait, int64(queueWait))
+	s.queueWait.observe(queueWait)
+}
+
+func (s *poolStats) done(runTime time.Duration, r Runnable, panicked bool) {
+	s.running.Add(-1)
+	s.completed.Add(1)
+	s.totalRunTime.Add(int64(runTime))
+	storeMax(&s.maxRunTime, int64(runTime))
+	s.runTime.observe(runTime)
+
+	switch {
+	case panicked:
+		s.panicked.Add(1)
+	case failureOf(r) != nil:
+		s.failed.Add(1)
+	}
+}
+
+func (s *poolStats) snapshot() PoolStats {
+	return PoolStats{
+		Running:        int(s.running.Load()),
+		Completed:      s.completed.Load(),
+		Failed:         s.failed.Load(),
+		Panicked:       s.panicked.Load(),
+		Rejected:       s.rejected.Load(),
+		PeakRunning:    s.peakRunning.Load(),
+		TotalQueueWait: time.Duration(s.totalQueueWait.Load()),
+		MaxQueueWait:   time.Duration(s.maxQueueWait.Load()),
+		TotalRunTime:   time.Duration(s.totalRunTime.Load()),
+		MaxRunTime:     time.Duration(s.maxRunTime.Load()),
+
+		QueueWaitHistogram: s.queueWait.snapshot(),
+		RunTimeHistogram:   s.runTime.snapshot(),
+	}
+}
+
+// failable the runnable which can report the error after run, like FutureTask.
+type failable interface {
+	failure() error
+}
+
+// failureOf return the error of the runnable after run, nil if succeed or not failable.
+func failureOf(r Runnable) error {
+	if f, ok := r.(failable); ok {
+		return f.failure()
+	}
+	return nil
+}
+
+// storeMax store val if greater than the current value.
+func storeMax(a *atomic.Int64, val int64) {
+	for {
+		current := a.Load()
+		if val <= current || a.CompareAndSwap(current, val) {
+			return
+		}
+	}
+}
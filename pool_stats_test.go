@@ -0,0 +1,78 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolExecutor_Stats(t *testing.T) {
+	executor := internalNewPoolExecutorService[int](WithMaxConcurrent(2), WithMaxBlockingTasks(1),
+		WithRejectionHandler(DiscardRejectionPolicy{}), WithErrorHandler(ErrorHandlerFunc(func(r Runnable, e error) {})))
+	defer executor.Shutdown(context.Background())
+
+	block := make(chan struct{})
+	started := make(chan struct{}, 2)
+	blocked := func(ctx context.Context) (int, error) {
+		started <- struct{}{}
+		<-block
+		return 1, nil
+	}
+	f1, err := executor.SubmitFunc(blocked)
+	require.NoError(t, err)
+	f2, err := executor.SubmitFunc(func(ctx context.Context) (int, error) {
+		started <- struct{}{}
+		<-block
+		return 0, errors.New("failed")
+	})
+	require.NoError(t, err)
+	<-started
+	<-started
+	f3, err := executor.SubmitFunc(func(ctx context.Context) (int, error) {
+		panic("panic")
+	})
+	require.NoError(t, err)
+	require.NoError(t, executor.ExecuteFunc(func(ctx context.Context) {}))
+
+	stats := executor.Stats()
+	require.Equal(t, 2, stats.Running)
+	require.Equal(t, 1, stats.Queued)
+	require.Equal(t, int64(1), stats.Rejected)
+
+	time.Sleep(10 * time.Millisecond)
+	close(block)
+	for _, f := range []Future[int]{f1, f2, f3} {
+		_, _ = f.Get(context.Background())
+	}
+
+	require.Eventually(t, func() bool {
+		return executor.Stats().Completed == 3
+	}, time.Second, time.Millisecond)
+	stats = executor.Stats()
+	require.Equal(t, 0, stats.Running)
+	require.Equal(t, 0, stats.Queued)
+	require.Equal(t, int64(1), stats.Failed)
+	require.Equal(t, int64(1), stats.Panicked)
+	require.Equal(t, int64(2), stats.PeakRunning)
+	require.GreaterOrEqual(t, stats.MaxRunTime, 10*time.Millisecond)
+	require.GreaterOrEqual(t, stats.MaxQueueWait, 10*time.Millisecond)
+	require.GreaterOrEqual(t, stats.TotalRunTime, stats.MaxRunTime)
+	require.Equal(t, stats.TotalRunTime/3, stats.AvgRunTime())
+}
+
+func Test_histogram(t *testing.T) {
+	var h histogram
+	h.observe(time.Millisecond)
+	h.observe(5 * time.Millisecond)
+	h.observe(20 * time.Millisecond)
+	h.observe(time.Minute)
+
+	got := h.snapshot()
+	require.Len(t, got.Counts, len(got.Bounds)+1)
+	require.Equal(t, int64(2), got.Counts[0])
+	require.Equal(t, int64(1), got.Counts[2])
+	require.Equal(t, int64(1), got.Counts[len(got.Bounds)])
+}
@@ -0,0 +1,109 @@
+package executors
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Progress the progress of a running callable.
+type Progress struct {
+	// Percent from 0 to 100
+	Percent float64 `json:"percent"`
+
+	Message string `json:"message,omitempty"`
+
+	// Partial the partial result, optional
+	Partial any `json:"partial,omitempty"`
+}
+
+type ProgressFunction func(progress Progress)
+
+// ProgressFuture a Future which can get the progress of the callable before completed.
+type ProgressFuture[T any] interface {
+	Future[T]
+
+	// Progress return the latest reported progress.
+	Progress() Progress
+
+	// OnProgress call progressFunc every time the callable reported progress until completed.
+	OnProgress(progressFunc ProgressFunction)
+}
+
+type progressReporterKey struct{}
+
+type progressReporter interface {
+	report(progress Progress)
+}
+
+// ReportProgress report progress to the ProgressFuture of the running callable.
+// Will return false if the callable is not submitted by SubmitWithProgress.
+func ReportProgress(ctx context.Context, progress Progress) bool {
+	reporter, ok := ctx.Value(progressReporterKey{}).(progressReporter)
+	if !ok {
+		return false
+	}
+	reporter.report(progress)
+	return true
+}
+
+// SubmitWithProgress submit a callable which can report progress by ReportProgress.
+func SubmitWithProgress[T any](executor Executor, callable Callable[T]) (ProgressFuture[T], error) {
+	f := NewProgressFutureTask[T](callable)
+	err := executor.Execute(f)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SubmitFuncWithProgress submit a func which can report progress by ReportProgress.
+func SubmitFuncWithProgress[T any](executor Executor, fn func(ctx context.Context) (T, error)) (ProgressFuture[T], error) {
+	return SubmitWithProgress[T](executor, CallableFunc[T](fn))
+}
+
+type ProgressFutureTask[T any] struct {
+	*FutureTask[T]
+	progress      atomic.Pointer[Progress]
+	locker        sync.Mutex
+	progressFuncs []ProgressFunction
+}
+
+func NewProgressFutureTask[T any](callable Callable[T]) *ProgressFutureTask[T] {
+	f := &ProgressFutureTask[T]{
+		FutureTask: NewFutureTask[T](callable),
+	}
+	f.progress.Store(&Progress{})
+	return f
+}
+
+// Run implement runnable
+func (f *ProgressFutureTask[T]) Run(ctx context.Context) {
+	f.FutureTask.Run(context.WithValue(ctx, progressReporterKey{}, progressReporter(f)))
+}
+
+func (f *ProgressFutureTask[T]) Progress() Progress {
+	return *f.progress.Load()
+}
+
+func (f *ProgressFutureTask[T]) OnProgress(progressFunc ProgressFunction) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+
+	f.progressFuncs = append(f.progressFuncs, progressFunc)
+}
+
+func (f *ProgressFutureTask[T]) report(progress Progress) {
+	if f.Completed() {
+		return
+	}
+	f.progress.Store(&progress)
+
+	f.locker.Lock()
+	progressFuncs := f.progressFuncs
+	f.locker.Unlock()
+
+	for _, progressFunc := range progressFuncs {
+		progressFunc(progress)
+	}
+}
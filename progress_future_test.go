@@ -0,0 +1,42 @@
+package executors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitWithProgress(t *testing.T) {
+	service := NewPoolExecutorService[string](WithMaxConcurrent(10))
+
+	step := make(chan struct{})
+	f, err := SubmitFuncWithProgress[string](service, func(ctx context.Context) (string, error) {
+		for i := 1; i <= 4; i++ {
+			<-step
+			ReportProgress(ctx, Progress{Percent: float64(i * 25), Message: "step"})
+		}
+		return "done", nil
+	})
+	require.NoError(t, err)
+
+	reported := make(chan Progress, 4)
+	f.OnProgress(func(progress Progress) {
+		reported <- progress
+	})
+	require.Equal(t, Progress{}, f.Progress())
+
+	step <- struct{}{}
+	require.Equal(t, float64(25), (<-reported).Percent)
+	require.Equal(t, float64(25), f.Progress().Percent)
+
+	for i := 0; i < 3; i++ {
+		step <- struct{}{}
+	}
+	got, err := f.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "done", got)
+	require.Equal(t, Progress{Percent: 100, Message: "step"}, f.Progress())
+
+	require.False(t, ReportProgress(context.Background(), Progress{}))
+}
@@ -0,0 +1,30 @@
+package executors
+
+// Promise a Future which is completed manually instead of running a Callable.
+// It's useful to bridge callback based API into Future.
+type Promise[T any] struct {
+	future *FutureTask[T]
+}
+
+func NewPromise[T any]() *Promise[T] {
+	return &Promise[T]{
+		future: newFutureTask[T](),
+	}
+}
+
+// Resolve complete the future with val.
+// Will return false if the future completed or canceled already.
+func (p *Promise[T]) Resolve(val T) bool {
+	return p.future.completeValue(val)
+}
+
+// Reject complete the future with err, err should not be nil.
+// Will return false if the future completed or canceled already.
+func (p *Promise[T]) Reject(err error) bool {
+	return p.future.completeError(err)
+}
+
+// Future return the future which will be completed by Resolve or Reject.
+func (p *Promise[T]) Future() Future[T] {
+	return p.future
+}
@@ -0,0 +1,58 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromise(t *testing.T) {
+	t.Run("resolve", func(t *testing.T) {
+		p := NewPromise[int]()
+		time.AfterFunc(10*time.Millisecond, func() {
+			require.True(t, p.Resolve(10))
+			require.False(t, p.Resolve(20))
+		})
+
+		got, err := p.Future().Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 10, got)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		targetErr := errors.New("rejected")
+		p := NewPromise[int]()
+		require.True(t, p.Reject(targetErr))
+		require.False(t, p.Resolve(10))
+
+		_, err := p.Future().Get(context.Background())
+		require.ErrorIs(t, err, targetErr)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		p := NewPromise[int]()
+		require.True(t, p.Future().Cancel())
+		require.False(t, p.Resolve(10))
+
+		_, err := p.Future().Get(context.Background())
+		require.ErrorIs(t, err, ErrFutureCanceled)
+	})
+
+	t.Run("combine with submitted future", func(t *testing.T) {
+		service := NewPoolExecutorService[int](WithMaxConcurrent(10))
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		p := NewPromise[int]()
+		go p.Resolve(2)
+
+		got, err := AllOf(context.Background(), f, p.Future()).Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, got)
+	})
+}
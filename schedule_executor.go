@@ -21,6 +21,7 @@ func NewPoolScheduleExecutor(opts ..._PoolExecutorOption) ScheduledExecutor {
 		dispatcher:   cron.NewDispatcher[Runnable](executor.opts.Logger),
 	}
 	scheduleExecutor.initTimerWheelOnce = sync.OnceFunc(scheduleExecutor.initTimerWheel)
+	scheduleExecutor.stopScheduleOnce = sync.OnceFunc(scheduleExecutor.stopSchedule)
 	return &scheduleExecutor
 }
 
@@ -29,6 +30,7 @@ type PoolScheduleExecutor struct {
 	tw                 *gxtime.TimerWheel
 	initTimerWheelOnce func()
 	cronScheduleOnce   sync.Once
+	stopScheduleOnce   func()
 	dispatcher         cron.Dispatcher[Runnable]
 }
 
@@ -103,6 +105,11 @@ func (p *PoolScheduleExecutor) ScheduleFuncAtCronRate(fn func(ctx context.Contex
 	return p.ScheduleAtCronRate(RunnableFunc(fn), rule)
 }
 
+// CronTasks return the snapshot of all cron tasks, include the last and next run time.
+func (p *PoolScheduleExecutor) CronTasks() []cron.TaskInfo {
+	return p.dispatcher.Tasks()
+}
+
 func (p *PoolScheduleExecutor) dispatchCRON() {
 	routine.GoWithRecovery(p.opts.Logger, func() {
 		p.opts.Logger.Debug("start to dispatch cron tasks")
@@ -124,17 +131,23 @@ func (p *PoolScheduleExecutor) dispatchCRON() {
 }
 
 func (p *PoolScheduleExecutor) Shutdown(ctx context.Context) error {
-	defer func() {
-		if p.tw != nil {
-			// wakeup tw
-			p.tw.Tick(1 * time.Millisecond)
-			p.tw.Close()
-		}
-	}()
-
-	defer func() {
-		p.dispatcher.Shutdown()
-	}()
+	defer p.stopScheduleOnce()
 
 	return p.PoolExecutor.Shutdown(ctx)
 }
+
+func (p *PoolScheduleExecutor) ShutdownNow() []Runnable {
+	defer p.stopScheduleOnce()
+
+	return p.PoolExecutor.ShutdownNow()
+}
+
+// stopSchedule stop the timer wheel and cron dispatcher
+func (p *PoolScheduleExecutor) stopSchedule() {
+	if p.tw != nil {
+		// wakeup tw
+		p.tw.Tick(1 * time.Millisecond)
+		p.tw.Close()
+	}
+	p.dispatcher.Shutdown()
+}
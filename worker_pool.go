@@ -0,0 +1,503 @@
+package executors
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+)
+
+var (
+	errPoolClosed   = errors.New("pool closed")
+	errPoolOverload = errors.New("pool overload")
+)
+
+// workerPool run the tasks of PoolExecutor in worker goroutines.
+type workerPool interface {
+	// Submit will return errPoolClosed if released, errPoolOverload if no space to run or queue the task.
+	Submit(task *poolTask) error
+
+	// Running return the count of running workers
+	Running() int
+
+	// Waiting return the count of queued tasks
+	Waiting() int
+
+	// Release stop all workers after the running tasks finished, and discard the queued tasks.
+	Release()
+
+	// Evict remove the oldest queued task, will return false if no queued task or not supported.
+	Evict() (*poolTask, bool)
+
+	// MaxWorkers return the max count of workers
+	MaxWorkers() int
+
+	// SetMaxWorkers change the max count of workers, ignore if n <= 0.
+	SetMaxWorkers(n int)
+
+	// MaxQueued return the max count of queued tasks, unlimited if 0
+	MaxQueued() int
+
+	// SetMaxQueued change the max count of queued tasks, unlimited if 0.
+	// Will return errors.ErrUnsupported if the pool can not change it at runtime.
+	SetMaxQueued(n int) error
+}
+
+// taskQueue the queue of pending tasks, not concurrency safe.
+type taskQueue interface {
+	Push(task *poolTask)
+	Pop() (*poolTask, bool)
+	// RemoveOldest remove the task pushed earliest
+	RemoveOldest() (*poolTask, bool)
+	Len() int
+}
+
+type fifoTaskQueue struct {
+	tasks []*poolTask
+}
+
+func newFIFOTaskQueue() *fifoTaskQueue {
+	return &fifoTaskQueue{}
+}
+
+func (q *fifoTaskQueue) Push(task *poolTask) {
+	q.tasks = append(q.tasks, task)
+}
+
+func (q *fifoTaskQueue) Pop() (*poolTask, bool) {
+	if len(q.tasks) == 0 {
+		return nil, false
+	}
+	task := q.tasks[0]
+	q.tasks[0] = nil
+	q.tasks = q.tasks[1:]
+	return task, true
+}
+
+func (q *fifoTaskQueue) RemoveOldest() (*poolTask, bool) {
+	return q.Pop()
+}
+
+func (q *fifoTaskQueue) Len() int {
+	return len(q.tasks)
+}
+
+// priorityTaskQueue take the task with the highest priority first, FIFO within the same priority.
+// The priority of queued task will be raised by 1 every aging duration if aging > 0.
+type priorityTaskQueue struct {
+	heap  taskHeap
+	aging time.Duration
+	seq   uint64
+}
+
+func newPriorityTaskQueue(aging time.Duration) *priorityTaskQueue {
+	q := &priorityTaskQueue{aging: aging}
+	q.heap.before = q.before
+	return q
+}
+
+// before return true if a should be taken before b.
+func (q *priorityTaskQueue) before(a, b *poolTask) bool {
+	if q.aging > 0 {
+		// the effective priority at now is priority + (now - submitted) / aging,
+		// compare priority * aging - submitted to keep the order unchanged as time goes by.
+		ka := int64(a.priority)*int64(q.aging) - a.submitted.UnixNano()
+		kb := int64(b.priority)*int64(q.aging) - b.submitted.UnixNano()
+		if ka != kb {
+			return ka > kb
+		}
+	} else if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.seq < b.seq
+}
+
+func (q *priorityTaskQueue) Push(task *poolTask) {
+	q.seq++
+	task.seq = q.seq
+	heap.Push(&q.heap, task)
+}
+
+func (q *priorityTaskQueue) Pop() (*poolTask, bool) {
+	if q.heap.Len() == 0 {
+		return nil, false
+	}
+	return heap.Pop(&q.heap).(*poolTask), true
+}
+
+// RemoveOldest find the task with the min seq, the oldest is usually not the head of heap.
+func (q *priorityTaskQueue) RemoveOldest() (*poolTask, bool) {
+	if q.heap.Len() == 0 {
+		return nil, false
+	}
+	oldest := 0
+	for i, task := range q.heap.tasks {
+		if task.seq < q.heap.tasks[oldest].seq {
+			oldest = i
+		}
+	}
+	return heap.Remove(&q.heap, oldest).(*poolTask), true
+}
+
+func (q *priorityTaskQueue) Len() int {
+	return q.heap.Len()
+}
+
+// taskHeap implement heap.Interface for priorityTaskQueue.
+type taskHeap struct {
+	tasks  []*poolTask
+	before func(a, b *poolTask) bool
+}
+
+func (h *taskHeap) Len() int {
+	return len(h.tasks)
+}
+
+func (h *taskHeap) Less(i, j int) bool {
+	return h.before(h.tasks[i], h.tasks[j])
+}
+
+func (h *taskHeap) Swap(i, j int) {
+	h.tasks[i], h.tasks[j] = h.tasks[j], h.tasks[i]
+}
+
+func (h *taskHeap) Push(x any) {
+	h.tasks = append(h.tasks, x.(*poolTask))
+}
+
+func (h *taskHeap) Pop() any {
+	n := len(h.tasks)
+	task := h.tasks[n-1]
+	h.tasks[n-1] = nil
+	h.tasks = h.tasks[:n-1]
+	return task
+}
+
+// nativePool a worker pool with an explicit task queue, like ThreadPoolExecutor in Java.
+// The workers will be created on demand, and exit after idle keepAlive.
+//
+// If coreWorkers is 0, will create workers up to maxWorkers before queueing the tasks,
+// and all idle workers will exit after keepAlive.
+//
+// Otherwise, will create workers up to coreWorkers before queueing the tasks,
+// and create workers above coreWorkers up to maxWorkers only if the queue is full,
+// the workers above coreWorkers will exit after idle keepAlive,
+// and the core workers will exit only if allowCoreTimeout.
+type nativePool struct {
+	locker sync.Mutex
+	queue  taskQueue
+	run    func(task *poolTask)
+	// coreWorkers the configured count of core workers, limited by maxWorkers if decreased at runtime
+	coreWorkers      int
+	maxWorkers       int
+	allowCoreTimeout bool
+	// maxQueued the max count of queued tasks, unlimited if 0
+	maxQueued int
+	keepAlive time.Duration
+	workers   int
+	// idle the wakeup channels of the idle workers not claimed by any queued task
+	idle   []chan struct{}
+	closed bool
+}
+
+func newNativePool(maxWorkers, maxQueued int, run func(task *poolTask)) *nativePool {
+	return &nativePool{
+		queue:      newFIFOTaskQueue(),
+		run:        run,
+		maxWorkers: maxWorkers,
+		maxQueued:  maxQueued,
+		keepAlive:  _DefaultPoolExecutorOptions.KeepAlive,
+	}
+}
+
+func (p *nativePool) Submit(task *poolTask) error {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if p.closed {
+		return errPoolClosed
+	}
+
+	full := p.maxQueued > 0 && p.queue.Len() >= p.maxQueued
+	switch {
+	case p.workers < p.core():
+		p.addWorker(task)
+	case len(p.idle) > 0 && !full:
+		p.queue.Push(task)
+		p.notifyIdle()
+	case p.core() == 0 && p.workers < p.maxWorkers:
+		p.addWorker(task)
+	case !full:
+		p.queue.Push(task)
+	case p.workers < p.maxWorkers:
+		p.addWorker(task)
+	default:
+		return errPoolOverload
+	}
+	return nil
+}
+
+// addWorker start a new worker with the first task, should be called with lock.
+func (p *nativePool) addWorker(task *poolTask) {
+	p.workers++
+	go p.worker(task)
+}
+
+// timeoutable return true if the idle worker can exit, should be called with lock.
+func (p *nativePool) timeoutable() bool {
+	return p.allowCoreTimeout || p.workers > p.core()
+}
+
+// core return the effective count of core workers, should be called with lock.
+func (p *nativePool) core() int {
+	return min(p.coreWorkers, p.maxWorkers)
+}
+
+// notifyIdle wakeup an idle worker to take the queued task, should be called with lock.
+// The worker is claimed once notified, so will not be counted as idle anymore.
+func (p *nativePool) notifyIdle() {
+	n := len(p.idle)
+	if n == 0 {
+		return
+	}
+	wakeup := p.idle[n-1]
+	p.idle[n-1] = nil
+	p.idle = p.idle[:n-1]
+	wakeup <- struct{}{}
+}
+
+// removeIdle remove the wakeup channel of the idle worker, will return false if the worker claimed already.
+// should be called with lock.
+func (p *nativePool) removeIdle(wakeup chan struct{}) bool {
+	for i, c := range p.idle {
+		if c == wakeup {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *nativePool) worker(task *poolTask) {
+	timer := time.NewTimer(p.keepAlive)
+	defer timer.Stop()
+	wakeup := make(chan struct{}, 1)
+
+	for task != nil {
+		p.runTask(task)
+		task = p.take(timer, wakeup)
+	}
+}
+
+// runTask run the task and recover the panic escaped from run, like the panic handler of ants pool,
+// so the worker keeps taking the queued tasks instead of crashing the process.
+func (p *nativePool) runTask(task *poolTask) {
+	defer func() {
+		// do nothing, handled by ErrorHandler already
+		_ = recover()
+	}()
+	p.run(task)
+}
+
+// take wait and take the next queued task, will return nil if the worker should exit.
+func (p *nativePool) take(timer *time.Timer, wakeup chan struct{}) *poolTask {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	for {
+		if p.workers > p.maxWorkers {
+			// max workers decreased
+			p.workers--
+			return nil
+		}
+		if task, ok := p.queue.Pop(); ok {
+			return task
+		}
+		if p.closed {
+			p.workers--
+			return nil
+		}
+
+		timer.Reset(p.keepAlive)
+		p.idle = append(p.idle, wakeup)
+		p.locker.Unlock()
+
+		expired := false
+		select {
+		case <-wakeup:
+		case <-timer.C:
+			expired = true
+		}
+
+		p.locker.Lock()
+		if !expired {
+			continue
+		}
+		if !p.removeIdle(wakeup) {
+			// claimed before expired
+			<-wakeup
+			continue
+		}
+		if p.queue.Len() == 0 && p.timeoutable() {
+			p.workers--
+			return nil
+		}
+	}
+}
+
+func (p *nativePool) Running() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.workers - len(p.idle)
+}
+
+func (p *nativePool) Waiting() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.queue.Len()
+}
+
+func (p *nativePool) Evict() (*poolTask, bool) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.queue.RemoveOldest()
+}
+
+func (p *nativePool) Release() {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for {
+		if _, ok := p.queue.Pop(); !ok {
+			break
+		}
+	}
+	// wakeup all idle workers to exit
+	for len(p.idle) > 0 {
+		p.notifyIdle()
+	}
+}
+
+func (p *nativePool) MaxWorkers() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.maxWorkers
+}
+
+// SetMaxWorkers the extra workers will exit after the running tasks finished,
+// and new workers will be created to run the queued tasks if increased.
+func (p *nativePool) SetMaxWorkers(n int) {
+	if n <= 0 {
+		return
+	}
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.maxWorkers = n
+	if p.closed {
+		return
+	}
+	for p.workers < p.maxWorkers && p.queue.Len() > len(p.idle) {
+		task, _ := p.queue.Pop()
+		p.addWorker(task)
+	}
+}
+
+func (p *nativePool) MaxQueued() int {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	return p.maxQueued
+}
+
+// SetMaxQueued the queued tasks will not be discarded if decreased.
+func (p *nativePool) SetMaxQueued(n int) error {
+	if n < 0 {
+		return nil
+	}
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.maxQueued = n
+	return nil
+}
+
+// antsPool run the tasks by ants.Pool, the tasks will be blocked in Submit instead of queued.
+type antsPool struct {
+	pool        *ants.Pool
+	run         func(task *poolTask)
+	maxBlocking int
+}
+
+func newAntsPool(maxWorkers, maxBlocking int, run func(task *poolTask)) *antsPool {
+	pool, err := ants.NewPool(maxWorkers,
+		ants.WithMaxBlockingTasks(maxBlocking),
+		// do nothing, will handle by ErrorHandler
+		ants.WithPanicHandler(func(cause interface{}) {}))
+	if err != nil {
+		panic(err)
+	}
+	return &antsPool{
+		pool:        pool,
+		run:         run,
+		maxBlocking: maxBlocking,
+	}
+}
+
+func (p *antsPool) Submit(task *poolTask) error {
+	err := p.pool.Submit(func() {
+		p.run(task)
+	})
+	switch {
+	case errors.Is(err, ants.ErrPoolClosed):
+		return errPoolClosed
+	case errors.Is(err, ants.ErrPoolOverload):
+		return errPoolOverload
+	default:
+		return err
+	}
+}
+
+func (p *antsPool) Running() int {
+	return p.pool.Running()
+}
+
+func (p *antsPool) Waiting() int {
+	return p.pool.Waiting()
+}
+
+// Evict ants.Pool can not remove the blocked task.
+func (p *antsPool) Evict() (*poolTask, bool) {
+	return nil, false
+}
+
+func (p *antsPool) Release() {
+	p.pool.Release()
+}
+
+func (p *antsPool) MaxWorkers() int {
+	return p.pool.Cap()
+}
+
+func (p *antsPool) SetMaxWorkers(n int) {
+	p.pool.Tune(n)
+}
+
+func (p *antsPool) MaxQueued() int {
+	return p.maxBlocking
+}
+
+// SetMaxQueued ants.Pool can not change the max blocking tasks at runtime.
+func (p *antsPool) SetMaxQueued(n int) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,418 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTask(fn func()) *poolTask {
+	return &poolTask{runnable: RunnableFunc(func(ctx context.Context) {
+		fn()
+	})}
+}
+
+func runTestTask(task *poolTask) {
+	task.runnable.Run(context.Background())
+}
+
+func TestNativePool(t *testing.T) {
+	t.Run("max workers", func(t *testing.T) {
+		pool := newNativePool(3, 0, runTestTask)
+		defer pool.Release()
+
+		var (
+			running atomic.Int32
+			peak    atomic.Int32
+			wg      sync.WaitGroup
+		)
+		for i := 0; i < 30; i++ {
+			wg.Add(1)
+			err := pool.Submit(newTestTask(func() {
+				defer wg.Done()
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+			}))
+			require.NoError(t, err)
+		}
+		wg.Wait()
+		require.Equal(t, int32(3), peak.Load())
+	})
+
+	t.Run("queue overload", func(t *testing.T) {
+		pool := newNativePool(1, 1, runTestTask)
+		defer pool.Release()
+
+		block := make(chan struct{})
+		defer close(block)
+		require.NoError(t, pool.Submit(newTestTask(func() { <-block })))
+		require.NoError(t, pool.Submit(newTestTask(func() { <-block })))
+		require.Equal(t, 1, pool.Running())
+		require.Equal(t, 1, pool.Waiting())
+		require.ErrorIs(t, pool.Submit(newTestTask(func() {})), errPoolOverload)
+	})
+
+	t.Run("idle workers exit", func(t *testing.T) {
+		pool := newNativePool(3, 0, runTestTask)
+		pool.keepAlive = 10 * time.Millisecond
+		defer pool.Release()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			require.NoError(t, pool.Submit(newTestTask(wg.Done)))
+		}
+		wg.Wait()
+
+		require.Eventually(t, func() bool {
+			pool.locker.Lock()
+			defer pool.locker.Unlock()
+			return pool.workers == 0
+		}, time.Second, time.Millisecond)
+
+		wg.Add(1)
+		require.NoError(t, pool.Submit(newTestTask(wg.Done)))
+		wg.Wait()
+	})
+
+	t.Run("core workers", func(t *testing.T) {
+		pool := newNativePool(3, 2, runTestTask)
+		pool.coreWorkers = 1
+		pool.keepAlive = 10 * time.Millisecond
+		defer pool.Release()
+
+		block := make(chan struct{})
+		// the first task run by the core worker, the next two queued
+		for i := 0; i < 3; i++ {
+			require.NoError(t, pool.Submit(newTestTask(func() { <-block })))
+		}
+		require.Equal(t, 1, pool.Running())
+		require.Equal(t, 2, pool.Waiting())
+
+		// queue full, create workers above core up to max
+		for i := 0; i < 2; i++ {
+			require.NoError(t, pool.Submit(newTestTask(func() { <-block })))
+		}
+		require.Equal(t, 3, pool.Running())
+		require.ErrorIs(t, pool.Submit(newTestTask(func() {})), errPoolOverload)
+
+		close(block)
+		// the workers above core exit after idle keepAlive
+		require.Eventually(t, func() bool {
+			pool.locker.Lock()
+			defer pool.locker.Unlock()
+			return pool.workers == 1 && pool.queue.Len() == 0
+		}, time.Second, time.Millisecond)
+		time.Sleep(30 * time.Millisecond)
+		pool.locker.Lock()
+		require.Equal(t, 1, pool.workers)
+		pool.locker.Unlock()
+	})
+
+	t.Run("burst with idle worker", func(t *testing.T) {
+		pool := newNativePool(4, 1, runTestTask)
+		defer pool.Release()
+
+		done := make(chan struct{})
+		require.NoError(t, pool.Submit(newTestTask(func() { close(done) })))
+		<-done
+		require.Eventually(t, func() bool {
+			pool.locker.Lock()
+			defer pool.locker.Unlock()
+			return len(pool.idle) == 1
+		}, time.Second, time.Millisecond)
+
+		block := make(chan struct{})
+		defer close(block)
+		rejected := 0
+		for i := 0; i < 20; i++ {
+			err := pool.Submit(newTestTask(func() { <-block }))
+			if errors.Is(err, errPoolOverload) {
+				rejected++
+				continue
+			}
+			require.NoError(t, err)
+		}
+		// the idle worker claimed by the first task, the others run by the new workers up to max
+		pool.locker.Lock()
+		require.Equal(t, 4, pool.workers)
+		require.LessOrEqual(t, pool.queue.Len(), 1)
+		pool.locker.Unlock()
+		require.GreaterOrEqual(t, rejected, 15)
+	})
+
+	t.Run("allow core timeout", func(t *testing.T) {
+		pool := newNativePool(2, 0, runTestTask)
+		pool.coreWorkers = 2
+		pool.allowCoreTimeout = true
+		pool.keepAlive = 10 * time.Millisecond
+		defer pool.Release()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			require.NoError(t, pool.Submit(newTestTask(wg.Done)))
+		}
+		wg.Wait()
+		require.Eventually(t, func() bool {
+			pool.locker.Lock()
+			defer pool.locker.Unlock()
+			return pool.workers == 0
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("set max workers", func(t *testing.T) {
+		pool := newNativePool(1, 0, runTestTask)
+		defer pool.Release()
+
+		block := make(chan struct{})
+		for i := 0; i < 3; i++ {
+			require.NoError(t, pool.Submit(newTestTask(func() { <-block })))
+		}
+		require.Equal(t, 1, pool.Running())
+		require.Equal(t, 2, pool.Waiting())
+
+		// the queued tasks run by the new workers
+		pool.SetMaxWorkers(3)
+		require.Equal(t, 3, pool.MaxWorkers())
+		require.Equal(t, 3, pool.Running())
+		require.Equal(t, 0, pool.Waiting())
+
+		// the extra workers exit after the running tasks finished
+		pool.SetMaxWorkers(1)
+		close(block)
+		require.Eventually(t, func() bool {
+			pool.locker.Lock()
+			defer pool.locker.Unlock()
+			return pool.workers <= 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("set max workers keep core workers", func(t *testing.T) {
+		pool := newNativePool(8, 0, runTestTask)
+		pool.coreWorkers = 4
+		defer pool.Release()
+
+		// decrease below the core workers, and then restore
+		pool.SetMaxWorkers(2)
+		pool.SetMaxWorkers(8)
+
+		block := make(chan struct{})
+		defer close(block)
+		for i := 0; i < 8; i++ {
+			require.NoError(t, pool.Submit(newTestTask(func() { <-block })))
+		}
+		require.Equal(t, 4, pool.Running())
+		require.Equal(t, 4, pool.Waiting())
+	})
+
+	t.Run("release", func(t *testing.T) {
+		pool := newNativePool(1, 0, runTestTask)
+
+		block := make(chan struct{})
+		var executed atomic.Bool
+		require.NoError(t, pool.Submit(newTestTask(func() { <-block })))
+		require.NoError(t, pool.Submit(newTestTask(func() { executed.Store(true) })))
+
+		pool.Release()
+		close(block)
+		require.ErrorIs(t, pool.Submit(newTestTask(func() {})), errPoolClosed)
+		require.Eventually(t, func() bool {
+			return pool.Running() == 0
+		}, time.Second, time.Millisecond)
+		require.False(t, executed.Load())
+	})
+}
+
+func TestPoolExecutor_AntsPool(t *testing.T) {
+	service := NewPoolExecutorService[int](WithMaxConcurrent(10), WithAntsPool())
+
+	f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	got, err := f.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, got)
+
+	require.NoError(t, service.Shutdown(context.Background()))
+	_, err = service.SubmitFunc(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.ErrorIs(t, err, ErrShutdown)
+}
+
+func TestPoolExecutor_CorePoolSize(t *testing.T) {
+	service := NewPoolExecutorService[int](WithCorePoolSize(2), WithMaxPoolSize(4),
+		WithMaxBlockingTasks(10), WithKeepAlive(10*time.Millisecond))
+	defer service.Shutdown(context.Background())
+
+	futures := make([]Future[int], 0, 10)
+	for i := range 10 {
+		f, err := service.SubmitFunc(func(ctx context.Context) (int, error) {
+			return i, nil
+		})
+		require.NoError(t, err)
+		futures = append(futures, f)
+	}
+	for i, f := range futures {
+		got, err := f.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, i, got)
+	}
+}
+
+func TestPoolExecutor_SetMaxConcurrent(t *testing.T) {
+	for name, opts := range map[string][]_PoolExecutorOption{
+		"native": nil,
+		"ants":   {WithAntsPool()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			executor := internalNewPoolExecutorService[int](append(opts, WithMaxConcurrent(1), WithMaxBlockingTasks(10))...)
+			defer executor.Shutdown(context.Background())
+
+			require.Equal(t, 1, executor.MaxConcurrent())
+			require.Equal(t, 10, executor.MaxBlockingTasks())
+
+			executor.SetMaxConcurrent(2)
+			require.Equal(t, 2, executor.MaxConcurrent())
+
+			block := make(chan struct{})
+			started := make(chan struct{}, 2)
+			for i := 0; i < 2; i++ {
+				require.NoError(t, executor.ExecuteFunc(func(ctx context.Context) {
+					started <- struct{}{}
+					<-block
+				}))
+			}
+			<-started
+			<-started
+			close(block)
+
+			err := executor.SetMaxBlockingTasks(5)
+			if name == "ants" {
+				require.ErrorIs(t, err, errors.ErrUnsupported)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 5, executor.MaxBlockingTasks())
+		})
+	}
+}
+
+func TestPriorityTaskQueue(t *testing.T) {
+	newTask := func(name string, priority int, submitted time.Time) *poolTask {
+		return &poolTask{
+			runnable:  RunnableFunc(func(ctx context.Context) {}),
+			ctx:       context.WithValue(context.Background(), traceKey{}, name),
+			priority:  priority,
+			submitted: submitted,
+		}
+	}
+	popAll := func(q taskQueue) []string {
+		var names []string
+		for {
+			task, ok := q.Pop()
+			if !ok {
+				return names
+			}
+			names = append(names, task.ctx.Value(traceKey{}).(string))
+		}
+	}
+
+	t.Run("priority and fifo", func(t *testing.T) {
+		q := newPriorityTaskQueue(0)
+		now := time.Now()
+		q.Push(newTask("low", 0, now))
+		q.Push(newTask("high1", 2, now))
+		q.Push(newTask("mid", 1, now))
+		q.Push(newTask("high2", 2, now))
+		require.Equal(t, 4, q.Len())
+		require.Equal(t, []string{"high1", "high2", "mid", "low"}, popAll(q))
+	})
+
+	t.Run("aging", func(t *testing.T) {
+		q := newPriorityTaskQueue(10 * time.Millisecond)
+		now := time.Now()
+		q.Push(newTask("high", 2, now))
+		// waited 3 aging durations, raised to 3
+		q.Push(newTask("old", 0, now.Add(-30*time.Millisecond)))
+		q.Push(newTask("low", 0, now))
+		require.Equal(t, []string{"old", "high", "low"}, popAll(q))
+	})
+
+	t.Run("remove oldest", func(t *testing.T) {
+		q := newPriorityTaskQueue(0)
+		now := time.Now()
+		q.Push(newTask("low", 0, now))
+		q.Push(newTask("high", 2, now))
+		q.Push(newTask("mid", 1, now))
+
+		task, ok := q.RemoveOldest()
+		require.True(t, ok)
+		require.Equal(t, "low", task.ctx.Value(traceKey{}))
+		require.Equal(t, []string{"high", "mid"}, popAll(q))
+		_, ok = q.RemoveOldest()
+		require.False(t, ok)
+	})
+}
+
+type priorityCallable struct {
+	CallableFunc[int]
+	priority int
+}
+
+func (c priorityCallable) Priority() int {
+	return c.priority
+}
+
+func TestPoolExecutor_PriorityQueue(t *testing.T) {
+	executor := internalNewPoolExecutorService[int](WithMaxConcurrent(1), WithPriorityQueue(), WithPriorityAging(0))
+	defer executor.Shutdown(context.Background())
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, executor.ExecuteFunc(func(ctx context.Context) {
+		close(started)
+		<-block
+	}))
+	<-started
+
+	var (
+		locker sync.Mutex
+		order  []int
+	)
+	record := func(i int) CallableFunc[int] {
+		return func(ctx context.Context) (int, error) {
+			locker.Lock()
+			defer locker.Unlock()
+			order = append(order, i)
+			return i, nil
+		}
+	}
+	f1, err := executor.SubmitWithPriority(record(1), 1)
+	require.NoError(t, err)
+	f2, err := executor.Submit(priorityCallable{CallableFunc: record(3), priority: 3})
+	require.NoError(t, err)
+	f3, err := executor.SubmitFunc(record(0))
+	require.NoError(t, err)
+	close(block)
+
+	for _, f := range []Future[int]{f1, f2, f3} {
+		_, err := f.Get(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, []int{3, 1, 0}, order)
+}